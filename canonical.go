@@ -0,0 +1,180 @@
+package json
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// RFC 8785 JSON Canonicalization Scheme (JCS)：
+// 成员按 key 的 UTF-16 码元排序，数字按 ECMAScript 的最短往返格式输出，
+// 字符串只做最少的转义，不允许重复的 key。
+
+// Canonicalize parses data and returns its RFC 8785 canonical form.
+func Canonicalize(data []byte) ([]byte, error) {
+	v, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return v.Canonical()
+}
+
+// Canonical returns the RFC 8785 canonical form of v. It fails on
+// duplicate object keys, invalid UTF-8 and non-finite numbers.
+func (v *jsonValue) Canonical() ([]byte, error) {
+	return appendCanonical(nil, v)
+}
+
+func appendCanonical(dst []byte, v *jsonValue) ([]byte, error) {
+	switch v.getValueType() {
+	case ValueNull:
+		return append(dst, "null"...), nil
+	case ValueFalse:
+		return append(dst, "false"...), nil
+	case ValueTrue:
+		return append(dst, "true"...), nil
+	case ValueNumber:
+		return appendESNumber(dst, v.n)
+	case ValueString:
+		return appendCanonicalString(dst, v.s)
+	case ValueArray:
+		if err := v.load(); err != nil {
+			return dst, err
+		}
+		dst = append(dst, '[')
+		for i, e := range v.Elements() {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			var err error
+			if dst, err = appendCanonical(dst, e); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, ']'), nil
+	case ValueObject:
+		if err := v.load(); err != nil {
+			return dst, err
+		}
+		order := make([]int, v.getObjectSize())
+		keys := make([][]uint16, len(order))
+		for i := range order {
+			order[i] = i
+			keys[i] = utf16.Encode([]rune(string(v.object.keys[i].s)))
+		}
+		sort.Slice(order, func(i, j int) bool { return compareUTF16(keys[order[i]], keys[order[j]]) < 0 })
+		dst = append(dst, '{')
+		for i, idx := range order {
+			if i > 0 {
+				if compareUTF16(keys[order[i-1]], keys[idx]) == 0 {
+					return dst, &UnsupportedValueError{"duplicate key " + strconv.Quote(string(v.object.keys[idx].s))}
+				}
+				dst = append(dst, ',')
+			}
+			var err error
+			if dst, err = appendCanonicalString(dst, v.object.keys[idx].s); err != nil {
+				return dst, err
+			}
+			dst = append(dst, ':')
+			if dst, err = appendCanonical(dst, v.object.values[idx]); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, '}'), nil
+	default:
+		return dst, &UnsupportedValueError{"invalid value"}
+	}
+}
+
+func compareUTF16(a, b []uint16) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// appendCanonicalString 只转义 "、\ 和控制字符
+func appendCanonicalString(dst []byte, s []byte) ([]byte, error) {
+	if !utf8.Valid(s) {
+		return dst, &UnsupportedValueError{"invalid UTF-8 string " + strconv.Quote(string(s))}
+	}
+	dst = append(dst, '"')
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c == '\b':
+			dst = append(dst, '\\', 'b')
+		case c == '\t':
+			dst = append(dst, '\\', 't')
+		case c == '\n':
+			dst = append(dst, '\\', 'n')
+		case c == '\f':
+			dst = append(dst, '\\', 'f')
+		case c == '\r':
+			dst = append(dst, '\\', 'r')
+		case c < 0x20:
+			dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return append(dst, '"'), nil
+}
+
+// appendESNumber 按 ECMAScript Number.prototype.toString 的规则格式化 f
+func appendESNumber(dst []byte, f float64) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return dst, &UnsupportedValueError{strconv.FormatFloat(f, 'g', -1, 64)}
+	}
+	if f == 0 {
+		return append(dst, '0'), nil
+	}
+	if f < 0 {
+		dst = append(dst, '-')
+		f = -f
+	}
+	// 最短往返的有效数字 digits 以及指数 n，使 f = 0.digits × 10^n
+	e := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp, _ := strings.Cut(e, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	x, _ := strconv.Atoi(exp)
+	n, k := x+1, len(digits)
+	switch {
+	case k <= n && n <= 21:
+		dst = append(dst, digits...)
+		for i := k; i < n; i++ {
+			dst = append(dst, '0')
+		}
+	case 0 < n && n <= 21:
+		dst = append(dst, digits[:n]...)
+		dst = append(dst, '.')
+		dst = append(dst, digits[n:]...)
+	case -6 < n && n <= 0:
+		dst = append(dst, '0', '.')
+		for i := n; i < 0; i++ {
+			dst = append(dst, '0')
+		}
+		dst = append(dst, digits...)
+	default:
+		dst = append(dst, digits[0])
+		if k > 1 {
+			dst = append(dst, '.')
+			dst = append(dst, digits[1:]...)
+		}
+		dst = append(dst, 'e')
+		if n-1 > 0 {
+			dst = append(dst, '+')
+		}
+		dst = strconv.AppendInt(dst, int64(n-1), 10)
+	}
+	return dst, nil
+}
@@ -0,0 +1,64 @@
+package json
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func testCanonical(t *testing.T, expect, source string) {
+	t.Helper()
+	b, err := Canonicalize([]byte(source))
+	if err != nil {
+		t.Errorf("Canonicalize %s error %s", source, err.Error())
+		return
+	}
+	assertEqual(t, expect, string(b))
+}
+
+func testESNumber(t *testing.T, expect string, f float64) {
+	t.Helper()
+	b, err := appendESNumber(nil, f)
+	if err != nil {
+		t.Errorf("appendESNumber %v error %s", f, err.Error())
+		return
+	}
+	assertEqual(t, expect, string(b))
+}
+
+func TestESNumber(t *testing.T) {
+	// RFC 8785 附录 B 中的样例
+	testESNumber(t, "0", 0)
+	testESNumber(t, "0", math.Copysign(0, -1))
+	testESNumber(t, "5e-324", math.Float64frombits(0x0000000000000001))
+	testESNumber(t, "-5e-324", math.Float64frombits(0x8000000000000001))
+	testESNumber(t, "1.7976931348623157e+308", math.Float64frombits(0x7fefffffffffffff))
+	testESNumber(t, "9007199254740992", math.Float64frombits(0x4340000000000000))
+	testESNumber(t, "-9007199254740992", math.Float64frombits(0xc340000000000000))
+	testESNumber(t, "295147905179352830000", math.Float64frombits(0x4430000000000000))
+	testESNumber(t, "1e+23", math.Float64frombits(0x44b52d02c7e14af6))
+	testESNumber(t, "9.999999999999997e+22", math.Float64frombits(0x44b52d02c7e14af5))
+	testESNumber(t, "0.000001", 1e-6)
+	testESNumber(t, "1e-7", 1e-7)
+	testESNumber(t, "333333333.3333333", math.Float64frombits(0x41b3de4355555555))
+	testESNumber(t, "1e+21", 1e21)
+	testESNumber(t, "100000000000000000000", 1e20)
+	testESNumber(t, "-1.5", -1.5)
+
+	_, err := appendESNumber(nil, math.Inf(1))
+	assertTrue(t, err != nil)
+}
+
+func TestCanonicalize(t *testing.T) {
+	testCanonical(t, `{"a":[1,"x",null,true],"b":{"c":1e+30,"d":-0.5}}`, ` { "b" : { "d" : -5e-1 , "c" : 1E30 } , "a" : [ 1.0 , "x" , null , true ] } `)
+	// RFC 8785 3.2.3 的排序样例，U+1F600 的代理对排在 U+FB33 之前
+	testCanonical(t,
+		"{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001F600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		`{"\u20ac":"Euro Sign","\r":"Carriage Return","\ufb33":"Hebrew Letter Dalet With Dagesh","1":"One","\ud83d\ude00":"Emoji: Grinning Face","\u0080":"Control","\u00f6":"Latin Small Letter O With Diaeresis"}`)
+	testCanonical(t, "\"\\u001f\\b\\t\\n\\f\\r\\\"\\\\/\u20ac\u2028\"", `"\u001F\b\t\n\f\r\"\\\/\u20ac\u2028"`)
+}
+
+func TestCanonicalDuplicateKey(t *testing.T) {
+	_, err := Canonicalize([]byte(`{"a":1,"b":2,"a":3}`))
+	assertTrue(t, err != nil && strings.Contains(err.Error(), `duplicate key "a"`))
+}
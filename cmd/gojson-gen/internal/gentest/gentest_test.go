@@ -0,0 +1,237 @@
+package gentest
+
+import (
+	stdjson "encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"json"
+)
+
+// plainItem 没有生成的方法，用来得到 json 包通过反射得到的结果
+type plainItem Item
+
+func newItem() *Item {
+	score := 9.5
+	return &Item{
+		Timestamps: Timestamps{Created: 1},
+		Owner:      &Owner{ID: "o1", Name: "hidden"},
+		Name:       "x\n\"y\"",
+		Level:      -3,
+		Price:      0.1,
+		Count:      7,
+		OK:         true,
+		Tags:       []Tag{"a", "b"},
+		Data:       []byte{1, 2, 3},
+		Parent:     &Item{Owner: &Owner{ID: "p"}, Name: "p", Tags: []Tag{}},
+		Children:   []*Item{{Owner: &Owner{ID: "c"}, Name: "c"}, nil},
+		Matrix:     [][]int{{1, 2}, nil, {}},
+		Score:      &score,
+		Color:      Color{R: 200},
+		Attrs:      map[string]int{"k": 1},
+		Any:        []interface{}{"s", 1.5},
+		When:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Wait:       time.Second,
+		Note:       "skipped",
+		Escaped:    "e",
+		Extra:      map[string]*Nested{"n": {Value: 4}},
+	}
+}
+
+func TestMarshalGenerated(t *testing.T) {
+	for _, x := range []*Item{newItem(), {}} {
+		got, err := json.Marshal(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := json.Marshal((*plainItem)(x))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("generated MarshalJSON\n got %s\nwant %s", got, want)
+		}
+	}
+
+	inf := Item{Children: []*Item{{Price: float32(math.Inf(1))}}}
+	_, err := json.Marshal(inf)
+	var uve *json.UnsupportedValueError
+	if !errors.As(err, &uve) {
+		t.Errorf("expected UnsupportedValueError, got %v", err)
+	}
+}
+
+// unmarshalBoth 分别用生成的方法和反射解码 s，返回两者的结果
+func unmarshalBoth(s string) (got, want Item, gotErr, wantErr string) {
+	errString := func(err error) string {
+		if err == nil {
+			return ""
+		}
+		return strings.ReplaceAll(err.Error(), "plainItem", "Item")
+	}
+	gotErr = errString(json.Unmarshal([]byte(s), &got))
+	wantErr = errString(json.Unmarshal([]byte(s), (*plainItem)(&want)))
+	return got, want, gotErr, wantErr
+}
+
+func TestUnmarshalGenerated(t *testing.T) {
+	data, err := json.Marshal(newItem())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		string(data),
+		`null`,
+		`{"ID": "o2", "NAME": "case", "tags": null, "children": [null], "parent": null, "score": null, "unknown": [1, {"a": 2}]}`,
+		`{"id": "o3", "data": null, "matrix": [[], null], "level": null, "color": "r7", "Name": "promoted"}`,
+	} {
+		got, want, gotErr, wantErr := unmarshalBoth(s)
+		if gotErr != wantErr {
+			t.Errorf("Unmarshal %s\n got error %s\nwant error %s", s, gotErr, wantErr)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Unmarshal %s\n got %+v\nwant %+v", s, got, want)
+		}
+	}
+}
+
+func TestUnmarshalGeneratedError(t *testing.T) {
+	for _, s := range []string{
+		`{"id": "o", "level": 200}`,
+		`{"id": "o", "count": -1}`,
+		`{"id": "o", "price": 1e100}`,
+		`{"id": "o", "tags": "a"}`,
+		`{"id": "o", "tags": [1]}`,
+		`{"id": "o", "ok": "true"}`,
+		`{"id": "o", "children": [{"id": "c", "name": 1}]}`,
+		`{"id": "o", "color": 1}`,
+		`{"id": "o", "data": "!"}`,
+		`[]`,
+		`{"id": "o",}`,
+		`{"id": "o"} {}`,
+		`{"name": "no id"}`,
+		`{"id": "o", "extra": {"n": {}}, "children": [{"id": "c"}, {}]}`,
+	} {
+		_, _, gotErr, wantErr := unmarshalBoth(s)
+		if gotErr == "" || gotErr != wantErr {
+			t.Errorf("Unmarshal %s\n got error %s\nwant error %s", s, gotErr, wantErr)
+		}
+	}
+}
+
+// timestamped 只嵌入了一个生成了方法的类型，自己没有生成方法，
+// Timestamps 的方法提升到 timestamped 上，与 encoding/json 中嵌入 time.Time 的情况相同
+type timestamped struct {
+	Timestamps
+	Name string
+}
+
+func TestEmbedGenerated(t *testing.T) {
+	x := timestamped{Timestamps{Created: 1}, "n"}
+	got, err := json.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := stdjson.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{"created":1}` || string(got) != string(want) {
+		t.Errorf("Marshal = %s, encoding/json = %s", got, want)
+	}
+	var y timestamped
+	if err := json.Unmarshal([]byte(`{"created":2,"Name":"n"}`), &y); err != nil || y != (timestamped{Timestamps{Created: 2}, ""}) {
+		t.Errorf("Unmarshal = %+v, %v", y, err)
+	}
+}
+
+func BenchmarkUnmarshalGenerated(b *testing.B) {
+	data, _ := json.Marshal(newItem())
+	for i := 0; i < b.N; i++ {
+		var x Item
+		if err := x.UnmarshalJSON(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalReflect(b *testing.B) {
+	data, _ := json.Marshal(newItem())
+	for i := 0; i < b.N; i++ {
+		var x plainItem
+		if err := json.Unmarshal(data, &x); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalGenerated(b *testing.B) {
+	x := newItem()
+	for i := 0; i < b.N; i++ {
+		if _, err := x.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalReflect(b *testing.B) {
+	x := (*plainItem)(newItem())
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(x); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// 以下基准经过 json.Marshal 和 json.Unmarshal，生成的类型走 Appender 和 LexerDecoder
+func BenchmarkJSONUnmarshalGenerated(b *testing.B) {
+	data, _ := json.Marshal(newItem())
+	for i := 0; i < b.N; i++ {
+		var x Item
+		if err := json.Unmarshal(data, &x); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJSONUnmarshalGeneratedField(b *testing.B) {
+	data, _ := json.Marshal(struct{ Items []*Item }{[]*Item{newItem(), newItem()}})
+	for i := 0; i < b.N; i++ {
+		var x struct{ Items []Item }
+		if err := json.Unmarshal(data, &x); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJSONUnmarshalReflectField(b *testing.B) {
+	data, _ := json.Marshal(struct{ Items []*Item }{[]*Item{newItem(), newItem()}})
+	for i := 0; i < b.N; i++ {
+		var x struct{ Items []plainItem }
+		if err := json.Unmarshal(data, &x); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJSONMarshalGenerated(b *testing.B) {
+	x := *newItem()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(x); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJSONMarshalReflect(b *testing.B) {
+	x := plainItem(*newItem())
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(x); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Package gentest holds the structs used to test the code that
+// gojson-gen generates.
+package gentest
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:generate go run json/cmd/gojson-gen types.go
+
+type Level int8
+
+type Tag string
+
+// Color 有自己的文本编解码方法，生成的代码交给 json 包处理
+type Color struct{ R uint8 }
+
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte("r" + strconv.Itoa(int(c.R))), nil
+}
+
+func (c *Color) UnmarshalText(b []byte) error {
+	n, err := strconv.ParseUint(strings.TrimPrefix(string(b), "r"), 10, 8)
+	c.R = uint8(n)
+	return err
+}
+
+type Timestamps struct {
+	Created int64 `json:"created"`
+	Updated int64 `json:"updated,omitempty"`
+}
+
+type Owner struct {
+	ID   string `json:"id,required"`
+	Name string // 与 Item.Name 冲突，层级浅的优先
+}
+
+type Item struct {
+	Timestamps
+	*Owner
+	Name     string             `json:"name"`
+	Level    Level              `json:"level,omitempty"`
+	Price    float32            `json:"price"`
+	Ratio    float64            `json:"ratio,omitempty"`
+	Count    uint16             `json:"count"`
+	OK       bool               `json:"ok"`
+	Tags     []Tag              `json:"tags"`
+	Data     []byte             `json:"data,omitempty"`
+	Parent   *Item              `json:"parent,omitempty"`
+	Children []*Item            `json:"children,omitempty"`
+	Matrix   [][]int            `json:"matrix,omitempty"`
+	Score    *float64           `json:"score"`
+	Color    Color              `json:"color"`
+	Attrs    map[string]int     `json:"attrs,omitempty"`
+	Any      interface{}        `json:"any,omitempty"`
+	When     time.Time          `json:"when"`
+	Since    time.Time          `json:"since,omitempty"` // 结构体从不为空
+	Wait     time.Duration      `json:"wait,omitempty"`
+	Stamps   Timestamps         `json:"stamps,omitempty"`
+	Note     string             `json:"-"`
+	Escaped  string             `json:"a\"b,omitempty"`
+	Extra    map[string]*Nested `json:"extra,omitempty"`
+	private  int
+}
+
+type Nested struct {
+	Value int `json:"value,required"`
+}
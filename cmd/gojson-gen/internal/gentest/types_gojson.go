@@ -0,0 +1,539 @@
+// Code generated by gojson-gen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strconv"
+
+	"json"
+)
+
+// MarshalJSON implements json.Marshaler.
+func (x Timestamps) MarshalJSON() ([]byte, error) {
+	return x.gojsonAppend(nil)
+}
+
+// AppendJSON implements json.Appender.
+func (x Timestamps) AppendJSON(b []byte) ([]byte, error) {
+	return x.gojsonAppend(b)
+}
+
+func (x *Timestamps) gojsonAppend(b []byte) ([]byte, error) {
+	var err error
+	start := len(b)
+	b = append(b, `,"created":`...)
+	b = strconv.AppendInt(b, x.Created, 10)
+	if x.Updated != 0 {
+		b = append(b, `,"updated":`...)
+		b = strconv.AppendInt(b, x.Updated, 10)
+	}
+	if len(b) == start {
+		b = append(b, '{')
+	} else {
+		b[start] = '{'
+	}
+	return append(b, '}'), err
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *Timestamps) UnmarshalJSON(data []byte) error {
+	l := json.NewLexer(data)
+	x.DecodeJSON(l)
+	return l.End()
+}
+
+var gojsonFieldsTimestamps = []string{"created", "updated"}
+
+// DecodeJSON implements json.LexerDecoder.
+func (x *Timestamps) DecodeJSON(l *json.Lexer) {
+	if l.Null() {
+		return
+	}
+	for key := range l.Members(reflect.TypeFor[Timestamps]()) {
+		switch l.Field(key, gojsonFieldsTimestamps) {
+		case 0:
+			if !l.Null() {
+				x.Created = l.Int(reflect.TypeFor[int64]())
+			}
+		case 1:
+			if !l.Null() {
+				x.Updated = l.Int(reflect.TypeFor[int64]())
+			}
+		default:
+			l.Skip()
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (x Owner) MarshalJSON() ([]byte, error) {
+	return x.gojsonAppend(nil)
+}
+
+// AppendJSON implements json.Appender.
+func (x Owner) AppendJSON(b []byte) ([]byte, error) {
+	return x.gojsonAppend(b)
+}
+
+func (x *Owner) gojsonAppend(b []byte) ([]byte, error) {
+	var err error
+	start := len(b)
+	b = append(b, `,"id":`...)
+	b = json.AppendString(b, x.ID)
+	b = append(b, `,"Name":`...)
+	b = json.AppendString(b, x.Name)
+	if len(b) == start {
+		b = append(b, '{')
+	} else {
+		b[start] = '{'
+	}
+	return append(b, '}'), err
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *Owner) UnmarshalJSON(data []byte) error {
+	l := json.NewLexer(data)
+	x.DecodeJSON(l)
+	return l.End()
+}
+
+var gojsonFieldsOwner = []string{"id", "Name"}
+
+// DecodeJSON implements json.LexerDecoder.
+func (x *Owner) DecodeJSON(l *json.Lexer) {
+	if l.Null() {
+		return
+	}
+	var seen [2]bool
+	for key := range l.Members(reflect.TypeFor[Owner]()) {
+		switch l.Field(key, gojsonFieldsOwner) {
+		case 0:
+			seen[0] = true
+			if !l.Null() {
+				x.ID = l.String(reflect.TypeFor[string]())
+			}
+		case 1:
+			if !l.Null() {
+				x.Name = l.String(reflect.TypeFor[string]())
+			}
+		default:
+			l.Skip()
+		}
+	}
+	if !seen[0] {
+		l.Missing("id")
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (x Item) MarshalJSON() ([]byte, error) {
+	return x.gojsonAppend(nil)
+}
+
+// AppendJSON implements json.Appender.
+func (x Item) AppendJSON(b []byte) ([]byte, error) {
+	return x.gojsonAppend(b)
+}
+
+func (x *Item) gojsonAppend(b []byte) ([]byte, error) {
+	var err error
+	start := len(b)
+	b = append(b, `,"created":`...)
+	b = strconv.AppendInt(b, x.Timestamps.Created, 10)
+	if x.Timestamps.Updated != 0 {
+		b = append(b, `,"updated":`...)
+		b = strconv.AppendInt(b, x.Timestamps.Updated, 10)
+	}
+	if x.Owner != nil {
+		b = append(b, `,"id":`...)
+		b = json.AppendString(b, x.Owner.ID)
+	}
+	if x.Owner != nil {
+		b = append(b, `,"Name":`...)
+		b = json.AppendString(b, x.Owner.Name)
+	}
+	b = append(b, `,"name":`...)
+	b = json.AppendString(b, x.Name)
+	if x.Level != 0 {
+		b = append(b, `,"level":`...)
+		b = strconv.AppendInt(b, int64(x.Level), 10)
+	}
+	b = append(b, `,"price":`...)
+	if b, err = json.AppendFloat(b, float64(x.Price), 32); err != nil {
+		return b, err
+	}
+	if x.Ratio != 0 {
+		b = append(b, `,"ratio":`...)
+		if b, err = json.AppendFloat(b, x.Ratio, 64); err != nil {
+			return b, err
+		}
+	}
+	b = append(b, `,"count":`...)
+	b = strconv.AppendUint(b, uint64(x.Count), 10)
+	b = append(b, `,"ok":`...)
+	b = strconv.AppendBool(b, x.OK)
+	b = append(b, `,"tags":`...)
+	if x.Tags == nil {
+		b = append(b, "null"...)
+	} else {
+		b = append(b, '[')
+		for i1, e2 := range x.Tags {
+			if i1 > 0 {
+				b = append(b, ',')
+			}
+			b = json.AppendString(b, string(e2))
+		}
+		b = append(b, ']')
+	}
+	if len(x.Data) != 0 {
+		b = append(b, `,"data":`...)
+		b = append(b, '"')
+		b = base64.StdEncoding.AppendEncode(b, x.Data)
+		b = append(b, '"')
+	}
+	if x.Parent != nil {
+		b = append(b, `,"parent":`...)
+		if b, err = x.Parent.gojsonAppend(b); err != nil {
+			return b, err
+		}
+	}
+	if len(x.Children) != 0 {
+		b = append(b, `,"children":`...)
+		b = append(b, '[')
+		for i3, e4 := range x.Children {
+			if i3 > 0 {
+				b = append(b, ',')
+			}
+			if e4 == nil {
+				b = append(b, "null"...)
+			} else {
+				if b, err = e4.gojsonAppend(b); err != nil {
+					return b, err
+				}
+			}
+		}
+		b = append(b, ']')
+	}
+	if len(x.Matrix) != 0 {
+		b = append(b, `,"matrix":`...)
+		b = append(b, '[')
+		for i5, e6 := range x.Matrix {
+			if i5 > 0 {
+				b = append(b, ',')
+			}
+			if e6 == nil {
+				b = append(b, "null"...)
+			} else {
+				b = append(b, '[')
+				for i7, e8 := range e6 {
+					if i7 > 0 {
+						b = append(b, ',')
+					}
+					b = strconv.AppendInt(b, int64(e8), 10)
+				}
+				b = append(b, ']')
+			}
+		}
+		b = append(b, ']')
+	}
+	b = append(b, `,"score":`...)
+	if x.Score == nil {
+		b = append(b, "null"...)
+	} else {
+		if b, err = json.AppendFloat(b, *x.Score, 64); err != nil {
+			return b, err
+		}
+	}
+	b = append(b, `,"color":`...)
+	raw9, err := json.Marshal(&x.Color)
+	if err != nil {
+		return b, err
+	}
+	b = append(b, raw9...)
+	if len(x.Attrs) != 0 {
+		b = append(b, `,"attrs":`...)
+		raw10, err := json.Marshal(&x.Attrs)
+		if err != nil {
+			return b, err
+		}
+		b = append(b, raw10...)
+	}
+	if x.Any != nil {
+		b = append(b, `,"any":`...)
+		raw11, err := json.Marshal(&x.Any)
+		if err != nil {
+			return b, err
+		}
+		b = append(b, raw11...)
+	}
+	b = append(b, `,"when":`...)
+	raw12, err := json.Marshal(&x.When)
+	if err != nil {
+		return b, err
+	}
+	b = append(b, raw12...)
+	if !json.Empty(&x.Since) {
+		b = append(b, `,"since":`...)
+		raw13, err := json.Marshal(&x.Since)
+		if err != nil {
+			return b, err
+		}
+		b = append(b, raw13...)
+	}
+	if !json.Empty(&x.Wait) {
+		b = append(b, `,"wait":`...)
+		raw14, err := json.Marshal(&x.Wait)
+		if err != nil {
+			return b, err
+		}
+		b = append(b, raw14...)
+	}
+	b = append(b, `,"stamps":`...)
+	if b, err = x.Stamps.gojsonAppend(b); err != nil {
+		return b, err
+	}
+	if x.Escaped != "" {
+		b = append(b, `,"a\"b":`...)
+		b = json.AppendString(b, x.Escaped)
+	}
+	if len(x.Extra) != 0 {
+		b = append(b, `,"extra":`...)
+		raw15, err := json.Marshal(&x.Extra)
+		if err != nil {
+			return b, err
+		}
+		b = append(b, raw15...)
+	}
+	if len(b) == start {
+		b = append(b, '{')
+	} else {
+		b[start] = '{'
+	}
+	return append(b, '}'), err
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *Item) UnmarshalJSON(data []byte) error {
+	l := json.NewLexer(data)
+	x.DecodeJSON(l)
+	return l.End()
+}
+
+var gojsonFieldsItem = []string{"created", "updated", "id", "Name", "name", "level", "price", "ratio", "count", "ok", "tags", "data", "parent", "children", "matrix", "score", "color", "attrs", "any", "when", "since", "wait", "stamps", "a\"b", "extra"}
+
+// DecodeJSON implements json.LexerDecoder.
+func (x *Item) DecodeJSON(l *json.Lexer) {
+	if l.Null() {
+		return
+	}
+	var seen [25]bool
+	for key := range l.Members(reflect.TypeFor[Item]()) {
+		switch l.Field(key, gojsonFieldsItem) {
+		case 0:
+			if !l.Null() {
+				x.Timestamps.Created = l.Int(reflect.TypeFor[int64]())
+			}
+		case 1:
+			if !l.Null() {
+				x.Timestamps.Updated = l.Int(reflect.TypeFor[int64]())
+			}
+		case 2:
+			seen[2] = true
+			if x.Owner == nil {
+				x.Owner = new(Owner)
+			}
+			if !l.Null() {
+				x.Owner.ID = l.String(reflect.TypeFor[string]())
+			}
+		case 3:
+			if x.Owner == nil {
+				x.Owner = new(Owner)
+			}
+			if !l.Null() {
+				x.Owner.Name = l.String(reflect.TypeFor[string]())
+			}
+		case 4:
+			if !l.Null() {
+				x.Name = l.String(reflect.TypeFor[string]())
+			}
+		case 5:
+			if !l.Null() {
+				x.Level = Level(l.Int(reflect.TypeFor[Level]()))
+			}
+		case 6:
+			if !l.Null() {
+				x.Price = float32(l.Float(reflect.TypeFor[float32]()))
+			}
+		case 7:
+			if !l.Null() {
+				x.Ratio = l.Float(reflect.TypeFor[float64]())
+			}
+		case 8:
+			if !l.Null() {
+				x.Count = uint16(l.Uint(reflect.TypeFor[uint16]()))
+			}
+		case 9:
+			if !l.Null() {
+				x.OK = l.Bool(reflect.TypeFor[bool]())
+			}
+		case 10:
+			if l.Null() {
+				x.Tags = nil
+			} else {
+				x.Tags = []Tag{}
+				for range l.Elements(reflect.TypeFor[[]Tag]()) {
+					var e16 Tag
+					if !l.Null() {
+						e16 = Tag(l.String(reflect.TypeFor[Tag]()))
+					}
+					x.Tags = append(x.Tags, e16)
+				}
+			}
+		case 11:
+			if l.Null() {
+				x.Data = nil
+			} else {
+				x.Data = l.Bytes(reflect.TypeFor[[]byte]())
+			}
+		case 12:
+			if l.Null() {
+				x.Parent = nil
+			} else {
+				if x.Parent == nil {
+					x.Parent = new(Item)
+				}
+				x.Parent.DecodeJSON(l)
+			}
+		case 13:
+			if l.Null() {
+				x.Children = nil
+			} else {
+				x.Children = []*Item{}
+				for range l.Elements(reflect.TypeFor[[]*Item]()) {
+					var e17 *Item
+					if l.Null() {
+						e17 = nil
+					} else {
+						if e17 == nil {
+							e17 = new(Item)
+						}
+						e17.DecodeJSON(l)
+					}
+					x.Children = append(x.Children, e17)
+				}
+			}
+		case 14:
+			if l.Null() {
+				x.Matrix = nil
+			} else {
+				x.Matrix = [][]int{}
+				for range l.Elements(reflect.TypeFor[[][]int]()) {
+					var e18 []int
+					if l.Null() {
+						e18 = nil
+					} else {
+						e18 = []int{}
+						for range l.Elements(reflect.TypeFor[[]int]()) {
+							var e19 int
+							if !l.Null() {
+								e19 = int(l.Int(reflect.TypeFor[int]()))
+							}
+							e18 = append(e18, e19)
+						}
+					}
+					x.Matrix = append(x.Matrix, e18)
+				}
+			}
+		case 15:
+			if l.Null() {
+				x.Score = nil
+			} else {
+				if x.Score == nil {
+					x.Score = new(float64)
+				}
+				*x.Score = l.Float(reflect.TypeFor[float64]())
+			}
+		case 16:
+			l.Decode(&x.Color)
+		case 17:
+			l.Decode(&x.Attrs)
+		case 18:
+			l.Decode(&x.Any)
+		case 19:
+			l.Decode(&x.When)
+		case 20:
+			l.Decode(&x.Since)
+		case 21:
+			l.Decode(&x.Wait)
+		case 22:
+			x.Stamps.DecodeJSON(l)
+		case 23:
+			if !l.Null() {
+				x.Escaped = l.String(reflect.TypeFor[string]())
+			}
+		case 24:
+			l.Decode(&x.Extra)
+		default:
+			l.Skip()
+		}
+	}
+	if !seen[2] {
+		l.Missing("id")
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (x Nested) MarshalJSON() ([]byte, error) {
+	return x.gojsonAppend(nil)
+}
+
+// AppendJSON implements json.Appender.
+func (x Nested) AppendJSON(b []byte) ([]byte, error) {
+	return x.gojsonAppend(b)
+}
+
+func (x *Nested) gojsonAppend(b []byte) ([]byte, error) {
+	var err error
+	start := len(b)
+	b = append(b, `,"value":`...)
+	b = strconv.AppendInt(b, int64(x.Value), 10)
+	if len(b) == start {
+		b = append(b, '{')
+	} else {
+		b[start] = '{'
+	}
+	return append(b, '}'), err
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *Nested) UnmarshalJSON(data []byte) error {
+	l := json.NewLexer(data)
+	x.DecodeJSON(l)
+	return l.End()
+}
+
+var gojsonFieldsNested = []string{"value"}
+
+// DecodeJSON implements json.LexerDecoder.
+func (x *Nested) DecodeJSON(l *json.Lexer) {
+	if l.Null() {
+		return
+	}
+	var seen [1]bool
+	for key := range l.Members(reflect.TypeFor[Nested]()) {
+		switch l.Field(key, gojsonFieldsNested) {
+		case 0:
+			seen[0] = true
+			if !l.Null() {
+				x.Value = int(l.Int(reflect.TypeFor[int]()))
+			}
+		default:
+			l.Skip()
+		}
+	}
+	if !seen[0] {
+		l.Missing("value")
+	}
+}
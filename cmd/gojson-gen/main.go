@@ -0,0 +1,808 @@
+// Command gojson-gen generates MarshalJSON and UnmarshalJSON methods for
+// Go struct types, so that encoding and decoding them does not look up
+// or walk their fields with reflection at run time. The generated code
+// still passes reflect.Type values to json.Lexer for error messages and
+// for the range checks of integers and floats. It also generates the
+// AppendJSON and DecodeJSON methods of json.Appender and
+// json.LexerDecoder, which json.Marshal and json.Unmarshal use instead
+// of MarshalJSON and UnmarshalJSON to avoid building a Value tree from
+// the generated output and copying it.
+//
+// Usage:
+//
+//	gojson-gen [-type T,U] [-o file] [-import path] file.go
+//
+// gojson-gen reads the struct definitions of the package containing
+// file.go with go/ast and writes the methods for the structs declared
+// in file.go, or only for the listed types, to file_gojson.go. It is
+// meant to be run by go generate:
+//
+//	//go:generate gojson-gen -type User user.go
+//
+// The generated code follows the same rules as json.Marshal and
+// json.Unmarshal: json struct tags with the omitempty and required
+// options, promotion of fields of embedded structs, and exact before
+// case-insensitive matching of member names. Fields of basic types,
+// []byte, slices, pointers and other generated structs are encoded and
+// decoded directly with json.Lexer; all other fields, including types
+// with their own Marshal/Unmarshal methods, go through json.Marshal and
+// json.Unmarshal.
+//
+// Like any MarshalJSON method, the generated methods are promoted to a
+// struct that embeds the type and has no methods of its own. Such a
+// struct is encoded and decoded as the embedded type alone and its
+// other fields are lost, as with an embedded time.Time. gojson-gen
+// warns about these structs in file.go; generate methods for them too.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"json"
+)
+
+const usage = "usage: gojson-gen [-type T,U] [-o file] [-import path] file.go\n"
+
+// header 是生成的文件的第一行，读取包时跳过以它开头的文件
+const header = "// Code generated by gojson-gen. DO NOT EDIT.\n"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run 执行命令行，返回进程的退出码：0 成功，1 生成失败，2 用法错误
+func run(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gojson-gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { fmt.Fprint(stderr, usage) }
+	typeList := fs.String("type", "", "comma-separated list of struct types; default all structs in file")
+	output := fs.String("o", "", "output file; default file_gojson.go")
+	importPath := fs.String("import", "json", "import path of the json package")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	file := fs.Arg(0)
+	if *output == "" {
+		*output = strings.TrimSuffix(file, ".go") + "_gojson.go"
+	}
+	var names []string
+	if *typeList != "" {
+		names = strings.Split(*typeList, ",")
+	}
+	src, warnings, err := generate(file, *output, names, *importPath)
+	if err != nil {
+		fmt.Fprintln(stderr, "gojson-gen:", err)
+		return 1
+	}
+	for _, w := range warnings {
+		fmt.Fprintln(stderr, "gojson-gen: warning:", w)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintln(stderr, "gojson-gen:", err)
+		return 1
+	}
+	return 0
+}
+
+// generator 保存一个包中的类型声明和生成中的代码
+type generator struct {
+	fset    *token.FileSet
+	pkg     string
+	specs   map[string]*ast.TypeSpec
+	methods map[string]map[string]bool // 类型名到方法名
+	targets map[string]bool            // 要生成方法的结构体
+	imports map[string]bool
+	buf     bytes.Buffer
+	tmp     int // 临时变量编号
+}
+
+// generate 为 file 中的结构体生成代码，names 为空时生成所有没有自己的编解码方法的结构体。
+// 读取包时跳过上次生成的 output。warnings 是不影响生成的问题
+func generate(file, output string, names []string, importPath string) (src []byte, warnings []string, err error) {
+	g := &generator{
+		fset:    token.NewFileSet(),
+		specs:   map[string]*ast.TypeSpec{},
+		methods: map[string]map[string]bool{},
+		targets: map[string]bool{},
+		imports: map[string]bool{},
+	}
+	target, err := parser.ParseFile(g.fset, file, nil, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	g.pkg = target.Name.Name
+	if err := g.loadPackage(filepath.Dir(file), file, output, target); err != nil {
+		return nil, nil, err
+	}
+	if len(names) == 0 {
+		for _, decl := range target.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if _, ok := ts.Type.(*ast.StructType); ok && ts.TypeParams == nil && !ts.Assign.IsValid() &&
+					!g.hasCodec(ts.Name.Name, true) && !g.hasCodec(ts.Name.Name, false) {
+					names = append(names, ts.Name.Name)
+				}
+			}
+		}
+	}
+	for _, name := range names {
+		ts := g.specs[name]
+		if ts == nil {
+			return nil, nil, fmt.Errorf("type %s not found", name)
+		}
+		if _, ok := ts.Type.(*ast.StructType); !ok || ts.TypeParams != nil || ts.Assign.IsValid() {
+			return nil, nil, fmt.Errorf("type %s is not a struct type", name)
+		}
+		if g.hasCodec(name, true) || g.hasCodec(name, false) {
+			return nil, nil, fmt.Errorf("type %s already has JSON or text methods", name)
+		}
+		g.targets[name] = true
+	}
+	if len(names) == 0 {
+		return nil, nil, errors.New("no struct types in " + file)
+	}
+
+	warnings = g.promoted(target)
+
+	g.imports["reflect"] = true
+	for _, name := range names {
+		if err := g.genType(name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "%s\npackage %s\n\nimport (\n", header, g.pkg)
+	std := []string{"encoding/base64", "reflect", "strconv"}
+	for _, imp := range std {
+		if g.imports[imp] {
+			fmt.Fprintf(&out, "\t%q\n", imp)
+		}
+	}
+	fmt.Fprintf(&out, "\n\t%q\n)\n", importPath)
+	out.Write(g.buf.Bytes())
+	src, err = format.Source(out.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("format generated code: %v", err)
+	}
+	return src, warnings, nil
+}
+
+// promoted 找出 target 中嵌入了一个要生成方法的结构体、自己却不生成方法的结构体。
+// 生成的方法会提升到这样的结构体上，编码时只剩下被嵌入的结构体的字段
+func (g *generator) promoted(target *ast.File) []string {
+	var warnings []string
+	for _, decl := range target.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || g.targets[ts.Name.Name] || g.hasCodec(ts.Name.Name, true) && g.hasCodec(ts.Name.Name, false) {
+				continue
+			}
+			// 嵌入了多个有 JSON 方法的类型时方法有歧义，不会提升
+			var embedded []string
+			for _, f := range st.Fields.List {
+				if len(f.Names) > 0 {
+					continue
+				}
+				t := f.Type
+				if star, ok := t.(*ast.StarExpr); ok {
+					t = star.X
+				}
+				if id, ok := t.(*ast.Ident); ok && g.targets[id.Name] {
+					embedded = append(embedded, id.Name)
+				}
+			}
+			if len(embedded) == 1 {
+				warnings = append(warnings, fmt.Sprintf("%s: %s embeds %s and is encoded and decoded by the methods generated for %s alone; generate methods for %s too",
+					g.fset.Position(ts.Pos()), ts.Name.Name, embedded[0], embedded[0], ts.Name.Name))
+			}
+		}
+	}
+	return warnings
+}
+
+// loadPackage 读取 dir 中与 target 同一个包的所有文件的类型声明和方法，
+// 跳过 output 和其它由 gojson-gen 生成的文件，其中的方法在重新生成时会被替换
+func (g *generator) loadPackage(dir, file, output string, target *ast.File) error {
+	files := []*ast.File{target}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") || sameFile(path, file) || sameFile(path, output) {
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.HasPrefix(src, []byte(header)) {
+			continue
+		}
+		f, err := parser.ParseFile(g.fset, path, src, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		if f.Name.Name == g.pkg {
+			files = append(files, f)
+		}
+	}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					ts := spec.(*ast.TypeSpec)
+					g.specs[ts.Name.Name] = ts
+				}
+			case *ast.FuncDecl:
+				if decl.Recv == nil || len(decl.Recv.List) == 0 {
+					continue
+				}
+				recv := decl.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if id, ok := recv.(*ast.Ident); ok {
+					if g.methods[id.Name] == nil {
+						g.methods[id.Name] = map[string]bool{}
+					}
+					g.methods[id.Name][decl.Name.Name] = true
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func sameFile(a, b string) bool {
+	sa, err1 := os.Stat(a)
+	sb, err2 := os.Stat(b)
+	return err1 == nil && err2 == nil && os.SameFile(sa, sb)
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// newVar 返回一个不重复的临时变量名
+func (g *generator) newVar(prefix string) string {
+	g.tmp++
+	return prefix + strconv.Itoa(g.tmp)
+}
+
+// genField 是参与编解码的字段，与 json 包中的 field 对应
+type genField struct {
+	name      string
+	tagged    bool
+	index     []int
+	path      []string // 从外层结构体到字段的 Go 字段名
+	ptr       []bool   // path 中各级是否为嵌入的指针
+	typ       ast.Expr
+	omitEmpty bool
+	required  bool
+}
+
+// typeFields 按 json 包的规则展开嵌入的结构体，处理字段名冲突
+func (g *generator) typeFields(name string) ([]genField, error) {
+	type level struct {
+		typ   string
+		index []int
+		path  []string
+		ptr   []bool
+	}
+	var current []level
+	next := []level{{typ: name}}
+	var count, nextCount map[string]int
+	visited := map[string]bool{}
+	var fields []genField
+	for len(next) > 0 {
+		current, next = next, nil
+		count, nextCount = nextCount, map[string]int{}
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+			st := g.specs[f.typ].Type.(*ast.StructType)
+			i := -1
+			for _, af := range st.Fields.List {
+				tag := ""
+				if af.Tag != nil {
+					s, err := strconv.Unquote(af.Tag.Value)
+					if err != nil {
+						return nil, err
+					}
+					tag = reflect.StructTag(s).Get("json")
+				}
+				names := af.Names
+				embedded := len(names) == 0
+				if embedded {
+					id := embeddedName(af.Type)
+					if id == nil {
+						return nil, fmt.Errorf("%s: embedded field %s is not supported", g.fset.Position(af.Pos()), types.ExprString(af.Type))
+					}
+					names = []*ast.Ident{id}
+				}
+				for _, id := range names {
+					i++
+					_, isPtr := af.Type.(*ast.StarExpr)
+					et := af.Type
+					if isPtr {
+						et = af.Type.(*ast.StarExpr).X
+					}
+					isStruct := false
+					if embedded {
+						if sel, ok := et.(*ast.SelectorExpr); ok {
+							return nil, fmt.Errorf("%s: embedded field %s from another package is not supported", g.fset.Position(af.Pos()), types.ExprString(sel))
+						}
+						ts := g.specs[id.Name]
+						if ts != nil {
+							_, isStruct = ts.Type.(*ast.StructType)
+						}
+						// 未导出的嵌入结构体仍然提升其导出字段
+						if !id.IsExported() && !isStruct {
+							continue
+						}
+					} else if !id.IsExported() {
+						continue
+					}
+					if tag == "-" {
+						continue
+					}
+					tagName, opts, _ := strings.Cut(tag, ",")
+					index := append(slices.Clone(f.index), i)
+					path := append(slices.Clone(f.path), id.Name)
+					ptr := append(slices.Clone(f.ptr), embedded && isPtr)
+					// 有 tag 的嵌入结构体和非结构体字段作为普通字段
+					if tagName != "" || !embedded || !isStruct {
+						jsonName := tagName
+						if jsonName == "" {
+							jsonName = id.Name
+						}
+						fields = append(fields, genField{
+							name:      jsonName,
+							tagged:    tagName != "",
+							index:     index,
+							path:      path,
+							ptr:       ptr,
+							typ:       af.Type,
+							omitEmpty: hasOption(opts, "omitempty"),
+							required:  hasOption(opts, "required"),
+						})
+						// 同一层级同一类型被嵌入多次时，再加一份使其在下面的冲突处理中被忽略
+						if count[f.typ] > 1 {
+							fields = append(fields, fields[len(fields)-1])
+						}
+						continue
+					}
+					nextCount[id.Name]++
+					if nextCount[id.Name] == 1 {
+						next = append(next, level{typ: id.Name, index: index, path: path, ptr: ptr})
+					}
+				}
+			}
+		}
+	}
+
+	// 同名字段中层级最浅的优先，同一层级中有 tag 的优先，否则全部忽略
+	slices.SortStableFunc(fields, func(a, b genField) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := len(a.index) - len(b.index); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.index, b.index)
+	})
+	out := fields[:0:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		group := fields[i:j]
+		i = j
+		if len(group) > 1 && len(group[0].index) == len(group[1].index) && group[0].tagged == group[1].tagged {
+			continue
+		}
+		out = append(out, group[0])
+	}
+	slices.SortFunc(out, func(a, b genField) int { return slices.Compare(a.index, b.index) })
+	return out, nil
+}
+
+// embeddedName 返回嵌入字段的类型名，不支持的形式返回 nil
+func embeddedName(t ast.Expr) *ast.Ident {
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch t := t.(type) {
+	case *ast.Ident:
+		return t
+	case *ast.SelectorExpr:
+		return t.Sel
+	}
+	return nil
+}
+
+func hasOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// kind 是生成代码时对字段类型的分类
+type kind int
+
+const (
+	kindFallback kind = iota // 交给 json.Marshal/json.Unmarshal
+	kindBool
+	kindInt
+	kindUint
+	kindFloat
+	kindString
+	kindBytes
+	kindSlice
+	kindPtr
+	kindStruct // 生成了方法的结构体
+)
+
+// typeInfo 描述字段类型的分类，name 是类型的 Go 写法
+type typeInfo struct {
+	kind kind
+	name string
+	bits int      // kindFloat 的位数
+	elem ast.Expr // kindSlice 和 kindPtr 的元素类型
+}
+
+var basicKinds = map[string]typeInfo{
+	"bool":    {kind: kindBool},
+	"string":  {kind: kindString},
+	"int":     {kind: kindInt},
+	"int8":    {kind: kindInt},
+	"int16":   {kind: kindInt},
+	"int32":   {kind: kindInt},
+	"int64":   {kind: kindInt},
+	"rune":    {kind: kindInt},
+	"uint":    {kind: kindUint},
+	"uint8":   {kind: kindUint},
+	"uint16":  {kind: kindUint},
+	"uint32":  {kind: kindUint},
+	"uint64":  {kind: kindUint},
+	"uintptr": {kind: kindUint},
+	"byte":    {kind: kindUint},
+	"float32": {kind: kindFloat, bits: 32},
+	"float64": {kind: kindFloat, bits: 64},
+}
+
+// resolve 对类型 t 分类，encode 表示用于生成而不是解析
+func (g *generator) resolve(t ast.Expr, encode bool) typeInfo {
+	name := types.ExprString(t)
+	switch t := t.(type) {
+	case *ast.ParenExpr:
+		return g.resolve(t.X, encode)
+	case *ast.Ident:
+		if ti, ok := basicKinds[t.Name]; ok && g.specs[t.Name] == nil {
+			ti.name = name
+			return ti
+		}
+		ts := g.specs[t.Name]
+		if ts == nil || ts.Assign.IsValid() || ts.TypeParams != nil || g.hasCodec(t.Name, encode) {
+			return typeInfo{kind: kindFallback, name: name}
+		}
+		if g.targets[t.Name] {
+			return typeInfo{kind: kindStruct, name: name}
+		}
+		// 底层是基本类型的命名类型
+		if id, ok := ts.Type.(*ast.Ident); ok {
+			if ti := g.resolve(id, encode); ti.kind >= kindBool && ti.kind <= kindString {
+				ti.name = name
+				return ti
+			}
+		}
+	case *ast.ArrayType:
+		if t.Len != nil {
+			break
+		}
+		if id, ok := t.Elt.(*ast.Ident); ok && (id.Name == "byte" || id.Name == "uint8") && g.specs[id.Name] == nil {
+			return typeInfo{kind: kindBytes, name: name}
+		}
+		if g.resolve(t.Elt, encode).kind != kindFallback {
+			return typeInfo{kind: kindSlice, name: name, elem: t.Elt}
+		}
+	case *ast.StarExpr:
+		if g.resolve(t.X, encode).kind != kindFallback {
+			return typeInfo{kind: kindPtr, name: name, elem: t.X}
+		}
+	}
+	return typeInfo{kind: kindFallback, name: name}
+}
+
+// hasCodec 判断类型是否自己实现了对应方向的 JSON 或文本编解码方法
+func (g *generator) hasCodec(name string, encode bool) bool {
+	if g.targets[name] {
+		return false
+	}
+	m := g.methods[name]
+	if encode {
+		return m["MarshalJSON"] || m["AppendJSON"] || m["MarshalText"]
+	}
+	return m["UnmarshalJSON"] || m["DecodeJSON"] || m["UnmarshalText"]
+}
+
+// conv 返回把类型为 from 的 expr 转成类型 to 的表达式，类型相同时不转换
+func conv(to, from, expr string) string {
+	if to == from {
+		return expr
+	}
+	return to + "(" + expr + ")"
+}
+
+// operand 把 *p 形式的表达式转成可以取地址或调用方法的形式
+func operand(expr string) string {
+	inner, ok := strings.CutPrefix(expr, "*")
+	if !ok {
+		return expr
+	}
+	if strings.HasPrefix(inner, "*") {
+		return "(" + inner + ")"
+	}
+	return inner
+}
+
+func (g *generator) genType(name string) error {
+	fields, err := g.typeFields(name)
+	if err != nil {
+		return err
+	}
+	g.printf("\n// MarshalJSON implements json.Marshaler.\n")
+	g.printf("func (x %s) MarshalJSON() ([]byte, error) {\nreturn x.gojsonAppend(nil)\n}\n", name)
+	g.printf("\n// AppendJSON implements json.Appender.\n")
+	g.printf("func (x %s) AppendJSON(b []byte) ([]byte, error) {\nreturn x.gojsonAppend(b)\n}\n", name)
+	g.printf("\nfunc (x *%s) gojsonAppend(b []byte) ([]byte, error) {\n", name)
+	g.printf("var err error\nstart := len(b)\n")
+	for _, f := range fields {
+		closes := 0
+		for i := 0; i < len(f.path)-1; i++ {
+			if f.ptr[i] {
+				g.printf("if x.%s != nil {\n", strings.Join(f.path[:i+1], "."))
+				closes++
+			}
+		}
+		src := "x." + strings.Join(f.path, ".")
+		// omitempty 的条件已经排除了 nil
+		nonNil := false
+		if f.omitEmpty {
+			if cond := g.nonEmpty(src, f.typ); cond != "" {
+				g.printf("if %s {\n", cond)
+				closes++
+				nonNil = true
+			}
+		}
+		g.printf("b = append(b, %s...)\n", quote(","+string(json.AppendString(nil, f.name))+":"))
+		g.encode(src, f.typ, nonNil)
+		g.printf("%s", strings.Repeat("}\n", closes))
+	}
+	g.printf("if len(b) == start {\nb = append(b, '{')\n} else {\nb[start] = '{'\n}\n")
+	g.printf("return append(b, '}'), err\n}\n")
+
+	g.printf("\n// UnmarshalJSON implements json.Unmarshaler.\n")
+	g.printf("func (x *%s) UnmarshalJSON(data []byte) error {\n", name)
+	g.printf("l := json.NewLexer(data)\nx.DecodeJSON(l)\nreturn l.End()\n}\n")
+	g.printf("\nvar gojsonFields%s = []string{", name)
+	required := false
+	for i, f := range fields {
+		if i > 0 {
+			g.printf(", ")
+		}
+		g.printf("%s", strconv.Quote(f.name))
+		required = required || f.required
+	}
+	g.printf("}\n")
+	g.printf("\n// DecodeJSON implements json.LexerDecoder.\n")
+	g.printf("func (x *%s) DecodeJSON(l *json.Lexer) {\nif l.Null() {\nreturn\n}\n", name)
+	if required {
+		g.printf("var seen [%d]bool\n", len(fields))
+	}
+	g.printf("for key := range l.Members(reflect.TypeFor[%s]()) {\n", name)
+	g.printf("switch l.Field(key, gojsonFields%s) {\n", name)
+	for i, f := range fields {
+		g.printf("case %d:\n", i)
+		if f.required {
+			g.printf("seen[%d] = true\n", i)
+		}
+		for j := 0; j < len(f.path)-1; j++ {
+			if f.ptr[j] {
+				p := "x." + strings.Join(f.path[:j+1], ".")
+				g.printf("if %s == nil {\n%s = new(%s)\n}\n", p, p, f.path[j])
+			}
+		}
+		g.decode("x."+strings.Join(f.path, "."), f.typ, false)
+	}
+	g.printf("default:\nl.Skip()\n}\n}\n")
+	for i, f := range fields {
+		if f.required {
+			g.printf("if !seen[%d] {\nl.Missing(%s)\n}\n", i, strconv.Quote(f.name))
+		}
+	}
+	g.printf("}\n")
+	return nil
+}
+
+// quote 把 s 写成 Go 字符串字面量，能用反引号时使用反引号
+func quote(s string) string {
+	if strconv.CanBackquote(s) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// nonEmpty 返回 omitempty 时判断 src 不为空的条件，结构体总是不为空，返回空字符串
+func (g *generator) nonEmpty(src string, t ast.Expr) string {
+	switch t := t.(type) {
+	case *ast.ParenExpr:
+		return g.nonEmpty(src, t.X)
+	case *ast.Ident:
+		if ti, ok := basicKinds[t.Name]; ok && g.specs[t.Name] == nil {
+			switch ti.kind {
+			case kindBool:
+				return src
+			case kindString:
+				return src + ` != ""`
+			}
+			return src + " != 0"
+		}
+		if ts := g.specs[t.Name]; ts != nil && ts.TypeParams == nil {
+			return g.nonEmpty(src, ts.Type)
+		}
+	case *ast.ArrayType, *ast.MapType:
+		return "len(" + src + ") != 0"
+	case *ast.StarExpr, *ast.InterfaceType:
+		return src + " != nil"
+	case *ast.StructType:
+		return ""
+	}
+	// 其它包的类型在运行时判断
+	return "!json.Empty(&" + src + ")"
+}
+
+// encode 生成把 src 写入 b 的语句，nonNil 表示已知 src 不为 nil
+func (g *generator) encode(src string, t ast.Expr, nonNil bool) {
+	ti := g.resolve(t, true)
+	switch ti.kind {
+	case kindBool:
+		g.imports["strconv"] = true
+		g.printf("b = strconv.AppendBool(b, %s)\n", conv("bool", ti.name, src))
+	case kindInt:
+		g.imports["strconv"] = true
+		g.printf("b = strconv.AppendInt(b, %s, 10)\n", conv("int64", ti.name, src))
+	case kindUint:
+		g.imports["strconv"] = true
+		g.printf("b = strconv.AppendUint(b, %s, 10)\n", conv("uint64", ti.name, src))
+	case kindFloat:
+		g.printf("if b, err = json.AppendFloat(b, %s, %d); err != nil {\nreturn b, err\n}\n", conv("float64", ti.name, src), ti.bits)
+	case kindString:
+		g.printf("b = json.AppendString(b, %s)\n", conv("string", ti.name, src))
+	case kindBytes, kindPtr, kindSlice:
+		if !nonNil {
+			g.printf("if %s == nil {\nb = append(b, \"null\"...)\n} else {\n", src)
+		}
+		switch ti.kind {
+		case kindBytes:
+			g.imports["encoding/base64"] = true
+			g.printf("b = append(b, '\"')\nb = base64.StdEncoding.AppendEncode(b, %s)\nb = append(b, '\"')\n", src)
+		case kindPtr:
+			g.encode("*"+src, ti.elem, false)
+		case kindSlice:
+			i, e := g.newVar("i"), g.newVar("e")
+			g.printf("b = append(b, '[')\nfor %s, %s := range %s {\nif %s > 0 {\nb = append(b, ',')\n}\n", i, e, src, i)
+			g.encode(e, ti.elem, false)
+			g.printf("}\nb = append(b, ']')\n")
+		}
+		if !nonNil {
+			g.printf("}\n")
+		}
+	case kindStruct:
+		g.printf("if b, err = %s.gojsonAppend(b); err != nil {\nreturn b, err\n}\n", operand(src))
+	default:
+		// 取地址使指针接收者的 MarshalJSON 也能生效，与可寻址的值在 json.Marshal 中的行为一致
+		raw := g.newVar("raw")
+		g.printf("%s, err := json.Marshal(&%s)\nif err != nil {\nreturn b, err\n}\nb = append(b, %s...)\n", raw, operand(src), raw)
+	}
+}
+
+// decode 生成把下一个值解码到 dst 的语句，nonNull 表示已知下一个值不是 null
+func (g *generator) decode(dst string, t ast.Expr, nonNull bool) {
+	ti := g.resolve(t, false)
+	var read string
+	switch ti.kind {
+	case kindBool:
+		read = conv(ti.name, "bool", "l.Bool(reflect.TypeFor["+ti.name+"]())")
+	case kindInt:
+		read = conv(ti.name, "int64", "l.Int(reflect.TypeFor["+ti.name+"]())")
+	case kindUint:
+		read = conv(ti.name, "uint64", "l.Uint(reflect.TypeFor["+ti.name+"]())")
+	case kindFloat:
+		read = conv(ti.name, "float64", "l.Float(reflect.TypeFor["+ti.name+"]())")
+	case kindString:
+		read = conv(ti.name, "string", "l.String(reflect.TypeFor["+ti.name+"]())")
+	case kindBytes, kindPtr, kindSlice:
+		// null 把 nil 写入 dst
+		if !nonNull {
+			g.printf("if l.Null() {\n%s = nil\n} else {\n", dst)
+		}
+		switch ti.kind {
+		case kindBytes:
+			g.printf("%s = l.Bytes(reflect.TypeFor[%s]())\n", dst, ti.name)
+		case kindPtr:
+			g.printf("if %s == nil {\n%s = new(%s)\n}\n", dst, dst, types.ExprString(ti.elem))
+			g.decode("*"+dst, ti.elem, true)
+		case kindSlice:
+			e := g.newVar("e")
+			g.printf("%s = %s{}\n", dst, ti.name)
+			g.printf("for range l.Elements(reflect.TypeFor[%s]()) {\nvar %s %s\n", ti.name, e, types.ExprString(ti.elem))
+			g.decode(e, ti.elem, false)
+			g.printf("%s = append(%s, %s)\n}\n", dst, dst, e)
+		}
+		if !nonNull {
+			g.printf("}\n")
+		}
+		return
+	case kindStruct:
+		g.printf("%s.DecodeJSON(l)\n", operand(dst))
+		return
+	default:
+		g.printf("l.Decode(&%s)\n", operand(dst))
+		return
+	}
+	// 基本类型遇到 null 时保持不变
+	if nonNull {
+		g.printf("%s = %s\n", dst, read)
+	} else {
+		g.printf("if !l.Null() {\n%s = %s\n}\n", dst, read)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerated 检查 internal/gentest 中提交的生成代码是最新的
+func TestGenerated(t *testing.T) {
+	file := filepath.Join("internal", "gentest", "types.go")
+	output := filepath.Join("internal", "gentest", "types_gojson.go")
+	src, _, err := generate(file, output, nil, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, old) {
+		t.Errorf("%s is out of date, run go generate", output)
+	}
+}
+
+func testGen(t *testing.T, src string, args ...string) (int, string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "types.go")
+	if err := os.WriteFile(file, []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var stderr bytes.Buffer
+	code := run(append(args, file), &stderr)
+	out, _ := os.ReadFile(filepath.Join(dir, "types_gojson.go"))
+	return code, string(out), strings.ReplaceAll(stderr.String(), dir+string(filepath.Separator), "")
+}
+
+func TestRun(t *testing.T) {
+	src := `package p
+
+type A struct {
+	X int ` + "`json:\"x\"`" + `
+}
+
+type B struct{ A }
+
+type T string
+
+func (t T) MarshalText() ([]byte, error) { return nil, nil }
+
+type C struct{ T T }
+
+func (c C) MarshalJSON() ([]byte, error) { return nil, nil }
+`
+	code, out, stderr := testGen(t, src)
+	if code != 0 || stderr != "" {
+		t.Fatalf("gojson-gen = %d, %s", code, stderr)
+	}
+	// 已有 MarshalJSON 的 C 不生成
+	for _, s := range []string{"func (x A) MarshalJSON", "func (x *B) UnmarshalJSON", "x.A.X = int(l.Int(reflect.TypeFor[int]()))"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output should contain %q", s)
+		}
+	}
+	if strings.Contains(out, "func (x C)") {
+		t.Error("output should not contain methods of C")
+	}
+
+	// 嵌入的 A 不生成方法时，其字段仍然提升到 B 中
+	code, out, _ = testGen(t, src, "-type", "B")
+	if code != 0 || strings.Contains(out, "func (x A)") || !strings.Contains(out, "x.A.X = int(") {
+		t.Errorf("-type B = %d\n%s", code, out)
+	}
+
+	// 只为 A 生成时，A 的方法会提升到 B 上
+	code, _, stderr = testGen(t, src, "-type", "A")
+	if code != 0 || stderr != "gojson-gen: warning: types.go:7:6: B embeds A and is encoded and decoded by the methods generated for A alone; generate methods for B too\n" {
+		t.Errorf("-type A = %d, %q", code, stderr)
+	}
+}
+
+// TestSkipGenerated 检查读取包时跳过以前生成的文件，即使文件名不是默认的输出文件
+func TestSkipGenerated(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "types.go")
+	old := header + "\npackage p\n\nfunc (x A) MarshalJSON() ([]byte, error) { return nil, nil }\n"
+	for name, src := range map[string]string{
+		"types.go": "package p\n\ntype A struct{ X int }\n",
+		"old.go":   old,
+		"other.go": "package p\n\nfunc (x *A) UnmarshalText([]byte) error { return nil }\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// other.go 不是生成的文件，其中的方法仍然生效
+	var stderr bytes.Buffer
+	if code := run([]string{"-type", "A", file}, &stderr); code != 1 || !strings.Contains(stderr.String(), "already has JSON or text methods") {
+		t.Errorf("gojson-gen = %d, %s", code, stderr.String())
+	}
+	os.Remove(filepath.Join(dir, "other.go"))
+	stderr.Reset()
+	if code := run([]string{file}, &stderr); code != 0 || stderr.Len() != 0 {
+		t.Errorf("gojson-gen = %d, %s", code, stderr.String())
+	}
+}
+
+func TestRunError(t *testing.T) {
+	for _, tt := range []struct {
+		src    string
+		args   []string
+		stderr string
+	}{
+		{"package p\ntype A struct{ X int }\n", []string{"-type", "Z"}, "gojson-gen: type Z not found\n"},
+		{"package p\ntype A int\n", []string{"-type", "A"}, "gojson-gen: type A is not a struct type\n"},
+		{"package p\ntype A int\n", nil, "gojson-gen: no struct types in "},
+		{"package p\ntype A struct{}\nfunc (a *A) UnmarshalJSON([]byte) error { return nil }\n", []string{"-type", "A"}, "gojson-gen: type A already has JSON or text methods\n"},
+		{"package p\nimport \"time\"\ntype A struct{ time.Time }\n", nil, "gojson-gen: types.go:3:16: embedded field time.Time from another package is not supported\n"},
+	} {
+		code, _, stderr := testGen(t, tt.src, tt.args...)
+		if code != 1 || !strings.HasPrefix(stderr, tt.stderr) {
+			t.Errorf("gojson-gen %v on %q = %d, %q, expected %q", tt.args, tt.src, code, stderr, tt.stderr)
+		}
+	}
+
+	var stderr bytes.Buffer
+	if code := run(nil, &stderr); code != 2 || stderr.String() != usage {
+		t.Errorf("gojson-gen = %d, %q", code, stderr.String())
+	}
+}
@@ -0,0 +1,297 @@
+// Command gojson formats, minifies and validates JSON documents.
+//
+// Usage:
+//
+//	gojson fmt [-indent n] [-tab] [-w] [file ...]
+//	gojson min [-w] [file ...]
+//	gojson validate [file ...]
+//	gojson query [-c] [-r] [-lines] filter [file ...]
+//
+// With no file arguments gojson reads standard input. The -w flag
+// rewrites each file in place instead of printing the result. Syntax
+// errors are reported as file:line:col: message and make gojson exit
+// with status 1.
+//
+// The query command applies a filter written in a subset of the jq
+// language (see json.Query) to every top-level value of its input and
+// prints each result, indented unless -c is given. With -r, string
+// results are printed without quotes. With -lines the input is read
+// as newline-delimited JSON one record at a time, so it can be used on
+// unbounded streams; malformed lines are reported and skipped.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"json"
+)
+
+const usage = `usage:
+	gojson fmt [-indent n] [-tab] [-w] [file ...]
+	gojson min [-w] [file ...]
+	gojson validate [file ...]
+	gojson query [-c] [-r] [-lines] filter [file ...]
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// command 描述一次子命令的执行环境
+type command struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	write  bool
+	// transform 为 nil 时只做校验
+	transform func(dst *bytes.Buffer, src []byte) error
+	// query 子命令的选项
+	query   *json.Query
+	compact bool
+	raw     bool
+	lines   bool
+}
+
+// run 执行命令行，返回进程的退出码：0 成功，1 存在错误，2 用法错误
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	c := &command{stdin: stdin, stdout: stdout, stderr: stderr}
+	fs := flag.NewFlagSet("gojson "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	switch args[0] {
+	case "fmt":
+		width := fs.Int("indent", 2, "indent width in spaces")
+		tab := fs.Bool("tab", false, "indent with tabs")
+		fs.BoolVar(&c.write, "w", false, "write result to the source file")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if *width < 0 {
+			fmt.Fprintln(stderr, "gojson fmt: negative indent width")
+			return 2
+		}
+		indent := strings.Repeat(" ", *width)
+		if *tab {
+			indent = "\t"
+		}
+		c.transform = func(dst *bytes.Buffer, src []byte) error {
+			return json.Indent(dst, src, "", indent)
+		}
+	case "min":
+		fs.BoolVar(&c.write, "w", false, "write result to the source file")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		c.transform = json.Compact
+	case "validate":
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+	case "query":
+		fs.BoolVar(&c.compact, "c", false, "compact output")
+		fs.BoolVar(&c.raw, "r", false, "print string results without quotes")
+		fs.BoolVar(&c.lines, "lines", false, "read newline-delimited JSON one line at a time")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			fmt.Fprintln(stderr, "gojson query: missing filter")
+			return 2
+		}
+		q, err := json.ParseQuery(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(stderr, "gojson query: %v\n", err)
+			return 2
+		}
+		c.query = q
+	default:
+		fmt.Fprintf(stderr, "gojson: unknown command %q\n", args[0])
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	files := fs.Args()
+	if c.query != nil {
+		files = files[1:]
+	}
+	if len(files) == 0 {
+		if c.write {
+			fmt.Fprintln(stderr, "gojson: cannot use -w with standard input")
+			return 2
+		}
+		files = []string{"-"}
+	}
+	code := 0
+	for _, name := range files {
+		if !c.file(name) {
+			code = 1
+		}
+	}
+	return code
+}
+
+// file 处理单个文件，"-" 表示标准输入；出错时输出诊断信息并返回 false
+func (c *command) file(name string) bool {
+	r, name, err := c.open(name)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "gojson: %v\n", err)
+		return false
+	}
+	defer r.Close()
+	if c.query != nil {
+		return c.runQuery(name, r)
+	}
+	src, err := io.ReadAll(r)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "gojson: %v\n", err)
+		return false
+	}
+	if c.transform == nil {
+		_, err = json.Parse(src)
+		return c.check(name, src, err)
+	}
+	var buf bytes.Buffer
+	if err = c.transform(&buf, src); err != nil {
+		return c.check(name, src, err)
+	}
+	buf.WriteByte('\n')
+	if !c.write {
+		_, err = c.stdout.Write(buf.Bytes())
+	} else if !bytes.Equal(src, buf.Bytes()) {
+		err = writeFile(name, buf.Bytes())
+	}
+	if err != nil {
+		fmt.Fprintf(c.stderr, "gojson: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// open 打开输入文件，"-" 表示标准输入，同时返回用于诊断信息的名字
+func (c *command) open(name string) (io.ReadCloser, string, error) {
+	if name == "-" {
+		return io.NopCloser(c.stdin), "<stdin>", nil
+	}
+	f, err := os.Open(name)
+	return f, name, err
+}
+
+// runQuery 对输入中的每个顶层值执行过滤器；求值出错时报告并继续处理下一个值
+func (c *command) runQuery(name string, r io.Reader) bool {
+	w := bufio.NewWriter(c.stdout)
+	defer w.Flush()
+	ok := true
+	if c.lines {
+		lr := json.NewLinesReader(r)
+		lr.SkipBlankLines()
+		lr.ContinueOnError()
+		reported := 0
+		for lr.Next() {
+			// 先报告 Next 跳过的坏行，保持诊断信息按行号排列
+			for ; reported < len(lr.Errors()); reported++ {
+				c.lineError(name, lr.Errors()[reported])
+				ok = false
+			}
+			if err := c.eval(w, lr.Value()); err != nil {
+				fmt.Fprintf(c.stderr, "%s:%d: %v\n", name, lr.Line(), err)
+				ok = false
+			}
+			// 逐行刷新输出，方便接在管道中处理不断增长的输入
+			w.Flush()
+		}
+		for ; reported < len(lr.Errors()); reported++ {
+			c.lineError(name, lr.Errors()[reported])
+			ok = false
+		}
+		if err := lr.Err(); err != nil {
+			fmt.Fprintf(c.stderr, "gojson: %v\n", err)
+			return false
+		}
+		return ok
+	}
+	src, err := io.ReadAll(r)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "gojson: %v\n", err)
+		return false
+	}
+	for doc, err := range json.Documents(src) {
+		if err != nil {
+			return c.check(name, src, err) && ok
+		}
+		if err := c.eval(w, doc.Value); err != nil {
+			line, col := json.LineCol(src, doc.Start)
+			fmt.Fprintf(c.stderr, "%s:%d:%d: %v\n", name, line, col, err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// eval 输出过滤器对 v 的所有结果
+func (c *command) eval(w io.Writer, v *json.Value) error {
+	for r, err := range c.query.Run(v) {
+		if err != nil {
+			return err
+		}
+		b, err := r.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		var s string
+		if c.raw && json.Unmarshal(b, &s) == nil {
+			b = []byte(s)
+		} else if !c.compact {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, b, "", "  "); err != nil {
+				return err
+			}
+			b = buf.Bytes()
+		}
+		if _, err := w.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *command) lineError(name string, err error) {
+	var le *json.LineError
+	if errors.As(err, &le) {
+		fmt.Fprintf(c.stderr, "%s:%d: %v\n", name, le.Line, le.Err)
+	} else {
+		fmt.Fprintf(c.stderr, "%s: %v\n", name, err)
+	}
+}
+
+// check 把解析错误输出为 file:line:col: msg 的形式
+func (c *command) check(name string, src []byte, err error) bool {
+	if err == nil {
+		return true
+	}
+	var se *json.SyntaxError
+	if errors.As(err, &se) {
+		line, col := json.LineCol(src, se.Offset)
+		fmt.Fprintf(c.stderr, "%s:%d:%d: %v\n", name, line, col, err)
+	} else {
+		fmt.Fprintf(c.stderr, "%s: %v\n", name, err)
+	}
+	return false
+}
+
+// writeFile 覆盖写入已有文件，保留原来的权限
+func writeFile(name string, data []byte) error {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, fi.Mode().Perm())
+}
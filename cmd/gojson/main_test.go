@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testRun(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func assertRun(t *testing.T, code int, stdout, stderr string, args []string, stdin string) {
+	t.Helper()
+	c, o, e := testRun(t, stdin, args...)
+	if c != code || o != stdout || e != stderr {
+		t.Errorf("gojson %v = (%d, %q, %q), expected (%d, %q, %q)", args, c, o, e, code, stdout, stderr)
+	}
+}
+
+func TestFmt(t *testing.T) {
+	in := `{"a":[1.0,{}],"b":"x"}`
+	assertRun(t, 0, "{\n  \"a\": [\n    1.0,\n    {}\n  ],\n  \"b\": \"x\"\n}\n", "", []string{"fmt"}, in)
+	assertRun(t, 0, "[\n    1\n]\n", "", []string{"fmt", "-indent", "4"}, "[1]")
+	assertRun(t, 0, "[\n\t1\n]\n", "", []string{"fmt", "-tab"}, "[1]")
+	assertRun(t, 0, "[\n1\n]\n", "", []string{"fmt", "-indent=0"}, "[1]")
+}
+
+func TestMin(t *testing.T) {
+	assertRun(t, 0, "{\"a\":[1,2],\"b\":null}\n", "", []string{"min"}, " {\n \"a\" : [ 1 , 2 ] ,\n \"b\" : null\n} ")
+}
+
+func TestValidate(t *testing.T) {
+	assertRun(t, 0, "", "", []string{"validate"}, `{"a":1}`)
+	assertRun(t, 1, "", "<stdin>:2:9: invalid character ] miss comma or curly bracket\n", []string{"validate"}, "{\n  \"a\": 1]\n}")
+	assertRun(t, 1, "", "<stdin>:1:9: invalid character ] miss comma or curly bracket\n", []string{"min"}, "{\"éé\": 1]")
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(good, []byte(`[ 1, 2 ]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte("[1,\n2,,]"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// 有一个文件出错时，其余文件照常处理
+	assertRun(t, 1, "", bad+":2:3: invalid character , number syntax invalid\n", []string{"validate", good, bad}, "")
+	assertRun(t, 0, "[1,2]\n", "", []string{"min", good}, "")
+
+	assertRun(t, 0, "", "", []string{"min", "-w", good}, "")
+	b, _ := os.ReadFile(good)
+	if string(b) != "[1,2]\n" {
+		t.Errorf("min -w wrote %q", b)
+	}
+	fi, _ := os.Stat(good)
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("min -w changed mode to %v", fi.Mode().Perm())
+	}
+	assertRun(t, 1, "", bad+":2:3: invalid character , number syntax invalid\n", []string{"fmt", "-w", bad}, "")
+	b, _ = os.ReadFile(bad)
+	if string(b) != "[1,\n2,,]" {
+		t.Errorf("fmt -w rewrote invalid file to %q", b)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	for _, args := range [][]string{nil, {"lint"}, {"fmt", "-w"}, {"fmt", "-indent", "-1"}, {"min", "-x"}} {
+		if code, _, _ := testRun(t, "", args...); code != 2 {
+			t.Errorf("gojson %v exit %d, expected 2", args, code)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	in := `{"items":[{"id":1,"name":"a"},{"id":2,"name":"b"}]}`
+	assertRun(t, 0, "1\n2\n", "", []string{"query", ".items[].id"}, in)
+	assertRun(t, 0, "{\n  \"id\": 2\n}\n", "", []string{"query", ".items[] | select(.id > 1) | {id}"}, in)
+	assertRun(t, 0, "[\"a\",\"b\"]\n", "", []string{"query", "-c", ".items | map(.name)"}, in)
+	assertRun(t, 0, "a\nb\n2\n", "", []string{"query", "-r", ".items[].name, (.items | length)"}, in)
+	// 多个顶层值依次处理
+	assertRun(t, 0, "1\n2\n", "", []string{"query", ".a"}, `{"a":1} {"a":2}`)
+	assertRun(t, 1, "1\n", "<stdin>:2:1: cannot index number with string\n", []string{"query", ".a"}, "{\"a\":1}\n3 ")
+	assertRun(t, 1, "1\n", "<stdin>:1:15: invalid character ] miss comma or curly bracket\n", []string{"query", ".a"}, `{"a":1} {"a":2]`)
+	assertRun(t, 2, "", "gojson query: invalid character \x00 miss term\n", []string{"query", ".a |"}, "")
+	assertRun(t, 2, "", "gojson query: missing filter\n", []string{"query"}, "")
+}
+
+func TestQueryLines(t *testing.T) {
+	in := "{\"level\":\"info\",\"n\":1}\n\n{\"level\":\"error\",\"n\":2}\n{oops}\n[]\n{\"level\":\"error\",\"n\":4}\n"
+	assertRun(t, 1, "2\n4\n",
+		"<stdin>:4: invalid character o miss key\n<stdin>:5: cannot index array with string\n",
+		[]string{"query", "-lines", "select(.level == \"error\") | .n"}, in)
+}
@@ -3,6 +3,7 @@ package json
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 )
@@ -10,7 +11,8 @@ import (
 // A SyntaxError is a description of a JSON syntax error.
 // Unmarshal will return a SyntaxError if the JSON can't be parsed.
 type SyntaxError struct {
-	msg string // description of error
+	msg    string // description of error
+	Offset int    // error occurred after reading Offset bytes
 }
 
 func (e *SyntaxError) Error() string { return e.msg }
@@ -48,6 +50,32 @@ type jsonParse struct {
 	data  []byte
 	off   int // next read offset in data
 	value *jsonValue
+	base  int  // data 在整个输入中的起始偏移，按需展开子节点时用于换算位置
+	lazy  bool // 按需解析：数组和对象只定位边界，访问时再解析
+	json5 bool // 按 JSON5 规范解析
+	jsonc bool // 按 JSONC 解析：允许注释和尾随逗号
+
+	spaced bool // skipWhiteSpace 是否跳过过空白，checkValue 据此判断是否需要压缩
+
+	onComment func(Comment) // 跳过注释时回调
+}
+
+// Parse parses the JSON-encoded data and returns the root Value.
+func Parse(data []byte) (*Value, error) {
+	// 先用两阶段解析，索引是 uint32，更大的输入直接逐字节解析；
+	// 出错时交给 jsonParse 重新解析，报告与之一致的错误
+	if uint64(len(data)) <= math.MaxUint32 {
+		if v, err := parseIndexed(data); err == nil {
+			return v, nil
+		}
+	}
+	d := new(jsonParse)
+	d.init(data)
+	v, err := d.parser()
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
 }
 
 func (d *jsonParse) init(data []byte) {
@@ -61,8 +89,9 @@ func (d *jsonParse) parser() (*jsonValue, error) {
 		return value, err
 	}
 	d.skipWhiteSpace()
-	if c := d.pop(); c != 0 {
-		return value, &SyntaxError{msg: "unexpected end of JSON input"}
+	// 不能用 pop() != 0 判断，输入中的 NUL 字节也会返回 0
+	if d.off < len(d.data) {
+		return value, &SyntaxError{msg: "unexpected end of JSON input", Offset: d.off}
 	}
 	return value, err
 }
@@ -70,6 +99,7 @@ func (d *jsonParse) parser() (*jsonValue, error) {
 func (d *jsonParse) parserValue() (*jsonValue, error) {
 	var err error
 	v := &jsonValue{}
+	start := d.off
 	c := d.pop()
 	switch c {
 	case 'n':
@@ -80,14 +110,36 @@ func (d *jsonParse) parserValue() (*jsonValue, error) {
 		err = d.parseLiteral([]byte("false"), v, ValueFalse)
 	case '"':
 		err = d.parseString(v)
+	case '\'':
+		if !d.json5 {
+			err = d.parseNumber(v)
+			break
+		}
+		err = d.parseString(v)
 	case '[':
-		err = d.parseArray(v)
+		if d.lazy {
+			err = d.deferValue(v, ValueArray)
+		} else {
+			err = d.parseArray(v)
+		}
 	case '{':
-		err = d.parseObject(v)
+		if d.lazy {
+			err = d.deferValue(v, ValueObject)
+		} else {
+			err = d.parseObject(v)
+		}
 	default:
 		err = d.parseNumber(v)
 	}
-	return v, err
+	if err != nil {
+		return v, err
+	}
+	v.start, v.end = d.base+start, d.base+d.off
+	// 严格模式下记录原文，JSON5 和 JSONC 的原文不一定是合法的 JSON
+	if !d.json5 && !d.jsonc {
+		v.text = d.data[start:d.off]
+	}
+	return v, nil
 }
 
 // except 判断 byte 是否如期待的一样
@@ -121,7 +173,16 @@ func (d *jsonParse) pop() byte {
 }
 
 func (d *jsonParse) skipWhiteSpace() {
-	for ; d.equal(' ') || d.equal('\t') || d.equal('\n') || d.equal('\r'); d.off++ {
+	start := d.off
+	for {
+		for ; d.equal(' ') || d.equal('\t') || d.equal('\n') || d.equal('\r'); d.off++ {
+		}
+		if !d.json5 && !d.jsonc || !d.skipExtraSpace() {
+			break
+		}
+	}
+	if d.off > start {
+		d.spaced = true
 	}
 }
 
@@ -142,7 +203,27 @@ func (d *jsonParse) parseLiteral(literal []byte, v *jsonValue, valueType ValueTy
 }
 
 func (d *jsonParse) parseNumber(v *jsonValue) error {
+	if d.json5 {
+		return d.parseNumber5(v)
+	}
 	start := d.off
+	if err := d.scanNumber(); err != nil {
+		return err
+	}
+	// 使用float64存储数字，同时保留原文
+	s := d.data[start:d.off]
+	n, err := convertNumber(string(s))
+	if err != nil {
+		return err
+	}
+	v.n = n
+	v.s = s
+	v.valueType = ValueNumber
+	return nil
+}
+
+// scanNumber 只校验数字语法并前进到数字末尾，不做转换
+func (d *jsonParse) scanNumber() error {
 	c := d.pop()
 	// 判断负数
 	if c == '-' {
@@ -183,14 +264,6 @@ func (d *jsonParse) parseNumber(v *jsonValue) error {
 			c = d.next()
 		}
 	}
-	// 使用float64存储数字
-	s := d.data[start:d.off]
-	n, err := convertNumber(string(s))
-	if err != nil {
-		return err
-	}
-	v.n = n
-	v.valueType = ValueNumber
 	return nil
 }
 
@@ -215,14 +288,18 @@ func (d *jsonParse) parseHex4() (rune, error) {
 
 func (d *jsonParse) parseString(v *jsonValue) error {
 	c := d.pop()
-	if err := d.except(c, '"'); err != nil {
+	quote := byte('"')
+	if d.json5 && c == '\'' {
+		quote = c
+	}
+	if err := d.except(c, quote); err != nil {
 		return err
 	}
 	c = d.next()
 	buf := bytes.NewBufferString("")
 	for {
 		switch c {
-		case '"':
+		case quote:
 			v.s = buf.Bytes()
 			c = d.next()
 			v.valueType = ValueString
@@ -271,7 +348,12 @@ func (d *jsonParse) parseString(v *jsonValue) error {
 				}
 				buf.WriteRune(r)
 			default:
-				return d.error(c, "invalid_string_escape")
+				if !d.json5 {
+					return d.error(c, "invalid_string_escape")
+				}
+				if err := d.parseEscape5(c, buf); err != nil {
+					return err
+				}
 			}
 			c = d.next()
 		case 0:
@@ -315,6 +397,12 @@ func (d *jsonParse) parseArray(v *jsonValue) error {
 		// 分析是否有分隔符
 		if c == ',' {
 			c = d.next()
+			if d.trailingComma(']') {
+				d.next()
+				v.array.len = len(v.array.values)
+				v.valueType = ValueArray
+				return nil
+			}
 		} else if c == ']' {
 			d.next()
 			v.array.len = len(v.array.values)
@@ -343,10 +431,11 @@ func (d *jsonParse) parseObject(v *jsonValue) error {
 	for {
 		// 解析key
 		d.skipWhiteSpace()
-		key := &jsonValue{}
-		if err := d.parseString(key); err != nil {
+		key := &jsonValue{start: d.base + d.off}
+		if err := d.parseKey(key); err != nil {
 			return d.error(c, "miss key")
 		}
+		key.end = d.base + d.off
 		// 解析 ：字符
 		d.skipWhiteSpace()
 		c = d.pop()
@@ -369,6 +458,12 @@ func (d *jsonParse) parseObject(v *jsonValue) error {
 		c = d.pop()
 		if c == ',' {
 			c = d.next()
+			if d.trailingComma('}') {
+				d.next()
+				v.object.size = len(v.object.values)
+				v.valueType = ValueObject
+				return nil
+			}
 		} else if c == '}' {
 			d.next()
 			v.object.size = len(v.object.values)
@@ -381,7 +476,7 @@ func (d *jsonParse) parseObject(v *jsonValue) error {
 }
 
 func (d *jsonParse) error(c byte, context string) error {
-	return &SyntaxError{msg: "invalid character " + string(c) + " " + context}
+	return &SyntaxError{msg: "invalid character " + string(c) + " " + context, Offset: d.off}
 }
 
 func isDigit(c byte) bool {
@@ -399,3 +494,19 @@ func convertNumber(s string) (float64, error) {
 	}
 	return n, err
 }
+
+// A Number represents a JSON number literal.
+type Number string
+
+// String returns the literal text of the number.
+func (n Number) String() string { return string(n) }
+
+// Float64 returns the number as a float64.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// Int64 returns the number as an int64.
+func (n Number) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
@@ -0,0 +1,839 @@
+package json
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// An UnsupportedTypeError is returned by Marshal when attempting
+// to encode an unsupported value type.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return "json: unsupported type: " + e.Type.String()
+}
+
+// An UnsupportedValueError is returned by Marshal when attempting
+// to encode an unsupported value.
+type UnsupportedValueError struct {
+	Str string
+}
+
+func (e *UnsupportedValueError) Error() string {
+	return "json: unsupported value: " + e.Str
+}
+
+// Marshaler is the interface implemented by types that
+// can marshal themselves into valid JSON. It matches
+// encoding/json.Marshaler, so existing implementations work unchanged.
+type Marshaler interface {
+	MarshalJSON() ([]byte, error)
+}
+
+// A MarshalerError represents an error from calling a MarshalJSON or
+// MarshalText method, or invalid JSON returned by MarshalJSON.
+type MarshalerError struct {
+	Type       reflect.Type
+	Err        error
+	sourceFunc string
+}
+
+func (e *MarshalerError) Error() string {
+	srcFunc := e.sourceFunc
+	if srcFunc == "" {
+		srcFunc = "MarshalJSON"
+	}
+	return "json: error calling " + srcFunc + " for type " + e.Type.String() + ": " + e.Err.Error()
+}
+
+func (e *MarshalerError) Unwrap() error { return e.Err }
+
+var (
+	marshalerType     = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Marshal returns the compact JSON encoding of v.
+//
+// If v, or a value reachable from it, implements Marshaler, Marshal
+// calls its MarshalJSON method and writes the result in compact form.
+// Otherwise, if it implements encoding.TextMarshaler, Marshal encodes
+// the result of MarshalText as a JSON string. Methods with pointer
+// receivers are used for addressable values.
+//
+// Map keys must be strings, integers or implement
+// encoding.TextMarshaler; members are sorted by the encoded key.
+func Marshal(v interface{}) ([]byte, error) {
+	e := &encodeState{}
+	if err := e.reflectValue(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+// MarshalJSON 把 Value 生成为紧凑的 JSON 文本
+func (v *jsonValue) MarshalJSON() ([]byte, error) {
+	return appendValue(nil, v)
+}
+
+// encodeState 生成器的状态，结果写入 buf
+type encodeState struct {
+	buf []byte
+}
+
+var valuePtrType = reflect.TypeOf((*Value)(nil))
+
+func (e *encodeState) reflectValue(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+	return typeEncoder(v.Type())(e, v)
+}
+
+// encoderFunc 把 v 生成到 e.buf，每种类型编译一次后缓存在 encoderCache 中
+type encoderFunc func(e *encodeState, v reflect.Value) error
+
+var encoderCache sync.Map // map[reflect.Type]encoderFunc
+
+// typeEncoder 返回类型 t 的生成函数。递归类型在编译期间先放入一个等待编译完成的占位函数
+func typeEncoder(t reflect.Type) encoderFunc {
+	if fi, ok := encoderCache.Load(t); ok {
+		return fi.(encoderFunc)
+	}
+	var (
+		wg sync.WaitGroup
+		f  encoderFunc
+	)
+	wg.Add(1)
+	fi, loaded := encoderCache.LoadOrStore(t, encoderFunc(func(e *encodeState, v reflect.Value) error {
+		wg.Wait()
+		return f(e, v)
+	}))
+	if loaded {
+		return fi.(encoderFunc)
+	}
+	f = newTypeEncoder(t, true)
+	wg.Done()
+	encoderCache.Store(t, f)
+	return f
+}
+
+// newTypeEncoder 编译类型 t 的生成函数，allowAddr 时可寻址的值使用指针接收者的方法
+func newTypeEncoder(t reflect.Type, allowAddr bool) encoderFunc {
+	switch {
+	case t == valuePtrType:
+		return valuePtrEncoder
+	case t == rawMessageType:
+		return rawMessageEncoder
+	case t.Kind() == reflect.Ptr && t.Elem() == rawMessageType:
+		return newPtrEncoder(t)
+	}
+	if t.Implements(marshalerType) {
+		return marshalerEncoder
+	}
+	if t.Kind() != reflect.Ptr && allowAddr && reflect.PointerTo(t).Implements(marshalerType) {
+		return newCondAddrEncoder(addrMarshalerEncoder, newTypeEncoder(t, false))
+	}
+	if t.Implements(textMarshalerType) {
+		return textMarshalerEncoder
+	}
+	if t.Kind() != reflect.Ptr && allowAddr && reflect.PointerTo(t).Implements(textMarshalerType) {
+		return newCondAddrEncoder(addrTextMarshalerEncoder, newTypeEncoder(t, false))
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return boolEncoder
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intEncoder
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return uintEncoder
+	case reflect.Float32, reflect.Float64:
+		return floatEncoder
+	case reflect.String:
+		return stringEncoder
+	case reflect.Interface:
+		return interfaceEncoder
+	case reflect.Ptr:
+		return newPtrEncoder(t)
+	case reflect.Slice:
+		return newSliceEncoder(t)
+	case reflect.Array:
+		return newArrayEncoder(t)
+	case reflect.Map:
+		return newMapEncoder(t)
+	case reflect.Struct:
+		return newStructEncoder(t)
+	default:
+		return unsupportedTypeEncoder
+	}
+}
+
+func valuePtrEncoder(e *encodeState, v reflect.Value) error {
+	if v.IsNil() {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+	b, err := appendValue(e.buf, v.Interface().(*Value))
+	e.buf = b
+	return err
+}
+
+func marshalerEncoder(e *encodeState, v reflect.Value) error {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+	return e.marshaler(v)
+}
+
+func addrMarshalerEncoder(e *encodeState, v reflect.Value) error {
+	return e.marshaler(v.Addr())
+}
+
+func textMarshalerEncoder(e *encodeState, v reflect.Value) error {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+	return e.textMarshaler(v)
+}
+
+func addrTextMarshalerEncoder(e *encodeState, v reflect.Value) error {
+	return e.textMarshaler(v.Addr())
+}
+
+// newCondAddrEncoder 可寻址时使用 canAddrEnc，否则使用 elseEnc
+func newCondAddrEncoder(canAddrEnc, elseEnc encoderFunc) encoderFunc {
+	return func(e *encodeState, v reflect.Value) error {
+		if v.CanAddr() {
+			return canAddrEnc(e, v)
+		}
+		return elseEnc(e, v)
+	}
+}
+
+func boolEncoder(e *encodeState, v reflect.Value) error {
+	e.buf = strconv.AppendBool(e.buf, v.Bool())
+	return nil
+}
+
+func intEncoder(e *encodeState, v reflect.Value) error {
+	e.buf = strconv.AppendInt(e.buf, v.Int(), 10)
+	return nil
+}
+
+func uintEncoder(e *encodeState, v reflect.Value) error {
+	e.buf = strconv.AppendUint(e.buf, v.Uint(), 10)
+	return nil
+}
+
+func floatEncoder(e *encodeState, v reflect.Value) error {
+	b, err := appendFloat(e.buf, v.Float(), v.Type().Bits())
+	if err != nil {
+		return err
+	}
+	e.buf = b
+	return nil
+}
+
+func stringEncoder(e *encodeState, v reflect.Value) error {
+	e.buf = appendString(e.buf, v.String())
+	return nil
+}
+
+func interfaceEncoder(e *encodeState, v reflect.Value) error {
+	if v.IsNil() {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+	return e.reflectValue(v.Elem())
+}
+
+func unsupportedTypeEncoder(e *encodeState, v reflect.Value) error {
+	return &UnsupportedTypeError{v.Type()}
+}
+
+func newPtrEncoder(t reflect.Type) encoderFunc {
+	elemEnc := typeEncoder(t.Elem())
+	return func(e *encodeState, v reflect.Value) error {
+		if v.IsNil() {
+			e.buf = append(e.buf, "null"...)
+			return nil
+		}
+		return elemEnc(e, v.Elem())
+	}
+}
+
+func newSliceEncoder(t reflect.Type) encoderFunc {
+	// []byte 生成为 base64 字符串
+	if t.Elem().Kind() == reflect.Uint8 {
+		return func(e *encodeState, v reflect.Value) error {
+			if v.IsNil() {
+				e.buf = append(e.buf, "null"...)
+				return nil
+			}
+			e.buf = append(e.buf, '"')
+			e.buf = base64.StdEncoding.AppendEncode(e.buf, v.Bytes())
+			e.buf = append(e.buf, '"')
+			return nil
+		}
+	}
+	arrayEnc := newArrayEncoder(t)
+	return func(e *encodeState, v reflect.Value) error {
+		if v.IsNil() {
+			e.buf = append(e.buf, "null"...)
+			return nil
+		}
+		return arrayEnc(e, v)
+	}
+}
+
+func newArrayEncoder(t reflect.Type) encoderFunc {
+	elemEnc := typeEncoder(t.Elem())
+	return func(e *encodeState, v reflect.Value) error {
+		e.buf = append(e.buf, '[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			if err := elemEnc(e, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, ']')
+		return nil
+	}
+}
+
+func newMapEncoder(t reflect.Type) encoderFunc {
+	switch t.Key().Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		if !t.Key().Implements(textMarshalerType) {
+			return unsupportedTypeEncoder
+		}
+	}
+	elemEnc := typeEncoder(t.Elem())
+	type member struct {
+		name  string
+		value reflect.Value
+	}
+	return func(e *encodeState, v reflect.Value) error {
+		if v.IsNil() {
+			e.buf = append(e.buf, "null"...)
+			return nil
+		}
+		members := make([]member, 0, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			name, err := resolveKeyName(it.Key())
+			if err != nil {
+				return err
+			}
+			members = append(members, member{name, it.Value()})
+		}
+		sort.Slice(members, func(i, j int) bool { return members[i].name < members[j].name })
+		e.buf = append(e.buf, '{')
+		for i, m := range members {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			e.buf = appendString(e.buf, m.name)
+			e.buf = append(e.buf, ':')
+			if err := elemEnc(e, m.value); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, '}')
+		return nil
+	}
+}
+
+func newStructEncoder(t reflect.Type) encoderFunc {
+	fields := cachedTypeFields(t).list
+	encoders := make([]encoderFunc, len(fields))
+	for i := range fields {
+		encoders[i] = typeEncoder(typeByIndex(t, fields[i].index))
+	}
+	return func(e *encodeState, v reflect.Value) error {
+		e.buf = append(e.buf, '{')
+		first := true
+		for i := range fields {
+			f := &fields[i]
+			fv, ok := fieldByIndex(v, f.index)
+			if !ok || f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			if !first {
+				e.buf = append(e.buf, ',')
+			}
+			first = false
+			e.buf = append(e.buf, f.nameJSON...)
+			if err := encoders[i](e, fv); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, '}')
+		return nil
+	}
+}
+
+// marshaler 调用 MarshalJSON，校验返回的内容并压缩后写入；
+// 实现了 Appender 的类型直接追加到 e.buf 后同样校验和压缩，*OrderedMap 直接写入
+func (e *encodeState) marshaler(v reflect.Value) error {
+	m := v.Interface()
+	if om, ok := m.(*OrderedMap); ok {
+		return om.encode(e)
+	}
+	if a, ok := m.(Appender); ok {
+		start := len(e.buf)
+		b, err := a.AppendJSON(e.buf)
+		if err == nil && len(b) < start {
+			err = errors.New("AppendJSON dropped the buffer it was given")
+		}
+		spaced := false
+		if err == nil {
+			spaced, err = checkValue(b[start:])
+		}
+		if err != nil {
+			return &MarshalerError{v.Type(), err, "AppendJSON"}
+		}
+		if spaced {
+			// 原地压缩，src 总在 dst 写入位置之后
+			b = appendCompact(b[:start], b[start:])
+		}
+		e.buf = b
+		return nil
+	}
+	b, err := m.(Marshaler).MarshalJSON()
+	if err != nil {
+		return &MarshalerError{v.Type(), err, "MarshalJSON"}
+	}
+	jv, err := Parse(b)
+	if err != nil {
+		return &MarshalerError{v.Type(), err, "MarshalJSON"}
+	}
+	if e.buf, err = appendValue(e.buf, jv); err != nil {
+		return &MarshalerError{v.Type(), err, "MarshalJSON"}
+	}
+	return nil
+}
+
+func (e *encodeState) textMarshaler(v reflect.Value) error {
+	b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return &MarshalerError{v.Type(), err, "MarshalText"}
+	}
+	e.buf = appendString(e.buf, string(b))
+	return nil
+}
+
+// fieldByIndex 沿 index 取字段，途中遇到 nil 的嵌入指针时返回 false
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	if len(index) == 1 {
+		return v.Field(index[0]), true
+	}
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
+
+// typeByIndex 返回沿 index 取到的字段的类型
+func typeByIndex(t reflect.Type, index []int) reflect.Type {
+	for _, i := range index {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		t = t.Field(i).Type
+	}
+	return t
+}
+
+// resolveKeyName 把 map 的 key 转成对象成员名，字符串类型优先于 TextMarshaler
+func resolveKeyName(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		if err != nil {
+			return "", &MarshalerError{k.Type(), err, "MarshalText"}
+		}
+		return string(b), nil
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	default:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+}
+
+// field 是结构体中参与编解码的字段，index 是从外层结构体出发的字段下标序列
+type field struct {
+	name      string
+	nameJSON  []byte // 生成时使用的 "name":
+	tagged    bool   // 名字来自 json tag
+	index     []int
+	typ       reflect.Type
+	omitEmpty bool
+	required  bool // 解码时必须出现
+}
+
+// typeFields 返回结构体 t 中参与编解码的字段，按 json tag 命名。
+// 嵌入结构体（或其指针）的字段会被提升，同名冲突按 encoding/json 的规则处理：
+// 层级浅的字段优先，同一层级有 tag 的优先，仍然无法区分时这些字段都被忽略
+func typeFields(t reflect.Type) []field {
+	// 按层级广度优先遍历嵌入的结构体
+	var current []field
+	next := []field{{typ: t}}
+	var count, nextCount map[reflect.Type]int
+	visited := map[reflect.Type]bool{}
+	var fields []field
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					// 未导出的嵌入结构体仍然提升其导出字段
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := parseTag(tag)
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				// 有 tag 的嵌入结构体和非结构体字段作为普通字段
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					tagged := name != ""
+					if name == "" {
+						name = sf.Name
+					}
+					fields = append(fields, field{
+						name:      name,
+						tagged:    tagged,
+						index:     index,
+						typ:       ft,
+						omitEmpty: opts.contains("omitempty"),
+						required:  opts.contains("required"),
+					})
+					// 同一层级同一类型被嵌入多次时，再加一份使其在下面的冲突处理中被忽略
+					if count[f.typ] > 1 {
+						fields = append(fields, fields[len(fields)-1])
+					}
+					continue
+				}
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, field{name: ft.Name(), index: index, typ: ft})
+				}
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		x := fields
+		if x[i].name != x[j].name {
+			return x[i].name < x[j].name
+		}
+		if len(x[i].index) != len(x[j].index) {
+			return len(x[i].index) < len(x[j].index)
+		}
+		if x[i].tagged != x[j].tagged {
+			return x[i].tagged
+		}
+		return compareIndex(x[i].index, x[j].index) < 0
+	})
+
+	// 每个名字只保留占优的字段
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		fi := fields[i]
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != fi.name {
+				break
+			}
+		}
+		if advance == 1 {
+			out = append(out, fi)
+			continue
+		}
+		if dominant, ok := dominantField(fields[i : i+advance]); ok {
+			out = append(out, dominant)
+		}
+	}
+	fields = out
+	sort.Slice(fields, func(i, j int) bool { return compareIndex(fields[i].index, fields[j].index) < 0 })
+	for i := range fields {
+		fields[i].nameJSON = append(appendString(nil, fields[i].name), ':')
+	}
+	return fields
+}
+
+// dominantField 从已排序的同名字段中选出占优的一个；
+// 前两个字段层级相同且同为有 tag 或无 tag 时没有占优字段
+func dominantField(fields []field) (field, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tagged == fields[1].tagged {
+		return field{}, false
+	}
+	return fields[0], true
+}
+
+func compareIndex(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return len(a) - len(b)
+}
+
+// structFields 是缓存的结构体字段信息
+type structFields struct {
+	list     []field
+	byName   map[string]int // 字段名到 list 下标
+	required bool           // 存在 required 字段
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+// cachedTypeFields 与 typeFields 相同，结果按类型缓存
+func cachedTypeFields(t reflect.Type) *structFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(*structFields)
+	}
+	fs := &structFields{list: typeFields(t), byName: map[string]int{}}
+	for i, f := range fs.list {
+		fs.byName[f.name] = i
+		fs.required = fs.required || f.required
+	}
+	f, _ := fieldCache.LoadOrStore(t, fs)
+	return f.(*structFields)
+}
+
+// lookup 优先精确匹配字段名，其次忽略大小写匹配，返回字段下标，没有时返回 -1
+func (fs *structFields) lookup(key []byte) int {
+	if i, ok := fs.byName[string(key)]; ok {
+		return i
+	}
+	for i := range fs.list {
+		if bytes.EqualFold([]byte(fs.list[i].name), key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// tagOptions 是 json tag 中名字之后的逗号分隔部分
+type tagOptions string
+
+func parseTag(tag string) (string, tagOptions) {
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, tagOptions(opts)
+}
+
+func (o tagOptions) contains(name string) bool {
+	s := string(o)
+	for s != "" {
+		var opt string
+		opt, s, _ = strings.Cut(s, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// appendValue 把 jsonValue 树生成为紧凑的 JSON 文本
+func appendValue(dst []byte, v *jsonValue) ([]byte, error) {
+	switch v.getValueType() {
+	case ValueNull:
+		return append(dst, "null"...), nil
+	case ValueFalse:
+		return append(dst, "false"...), nil
+	case ValueTrue:
+		return append(dst, "true"...), nil
+	case ValueNumber:
+		// 严格模式解析的数字保留原文，避免精度损失
+		if len(v.s) > 0 {
+			return append(dst, v.s...), nil
+		}
+		return appendFloat(dst, v.n, 64)
+	case ValueString:
+		return appendString(dst, string(v.s)), nil
+	case ValueArray:
+		if err := v.load(); err != nil {
+			return dst, err
+		}
+		dst = append(dst, '[')
+		for i, e := range v.Elements() {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			var err error
+			if dst, err = appendValue(dst, e); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, ']'), nil
+	case ValueObject:
+		if err := v.load(); err != nil {
+			return dst, err
+		}
+		dst = append(dst, '{')
+		for i := 0; i < v.getObjectSize(); i++ {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendString(dst, string(v.object.keys[i].s))
+			dst = append(dst, ':')
+			var err error
+			if dst, err = appendValue(dst, v.object.values[i]); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, '}'), nil
+	default:
+		return dst, &UnsupportedValueError{"invalid value"}
+	}
+}
+
+// appendFloat 按 encoding/json 的规则格式化浮点数
+func appendFloat(dst []byte, f float64, bits int) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return dst, &UnsupportedValueError{strconv.FormatFloat(f, 'g', -1, bits)}
+	}
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	dst = strconv.AppendFloat(dst, f, format, -1, bits)
+	if format == 'e' {
+		// 把 e-09 整理成 e-9
+		n := len(dst)
+		if n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst, nil
+}
+
+const hex = "0123456789abcdef"
+
+// appendString 生成带引号的 JSON 字符串，控制字符、U+2028、U+2029 和非法 UTF-8 会被转义
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
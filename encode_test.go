@@ -0,0 +1,264 @@
+package json
+
+import (
+	"errors"
+	"math"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testMarshal(t *testing.T, expect string, v interface{}) {
+	t.Helper()
+	b, err := Marshal(v)
+	if err != nil {
+		t.Errorf("Marshal %v error %s", v, err.Error())
+		return
+	}
+	assertEqual(t, expect, string(b))
+}
+
+type marshalUser struct {
+	Name    string          `json:"name"`
+	Age     int             `json:"age,omitempty"`
+	Tags    []string        `json:"tags"`
+	Extra   map[string]bool `json:"extra,omitempty"`
+	Secret  string          `json:"-"`
+	Friend  *marshalUser    `json:"friend,omitempty"`
+	Scores  [2]float64      `json:"scores"`
+	Data    []byte          `json:"data,omitempty"`
+	Default string
+	private int
+}
+
+func TestMarshal(t *testing.T) {
+	testMarshal(t, "null", nil)
+	testMarshal(t, "true", true)
+	testMarshal(t, "-12", int8(-12))
+	testMarshal(t, "12", uint(12))
+	testMarshal(t, "1.5", 1.5)
+	testMarshal(t, "1e+21", 1e21)
+	testMarshal(t, "1e-7", 1e-7)
+	testMarshal(t, "0.1", float32(0.1))
+	testMarshal(t, `"a\"\\\n\u0001\u2028"`, "a\"\\\n\x01\u2028")
+	testMarshal(t, `[1,2]`, []int{1, 2})
+	testMarshal(t, `null`, []int(nil))
+	testMarshal(t, `{"a":1,"b":2}`, map[string]int{"b": 2, "a": 1})
+	testMarshal(t, `{"name":"bob","tags":null,"friend":{"name":"amy","age":3,"tags":["x"],"scores":[0,0],"Default":""},"scores":[1,2.5],"data":"AQI=","Default":"d"}`,
+		&marshalUser{
+			Name:    "bob",
+			Secret:  "s",
+			Friend:  &marshalUser{Name: "amy", Age: 3, Tags: []string{"x"}},
+			Scores:  [2]float64{1, 2.5},
+			Data:    []byte{1, 2},
+			Default: "d",
+			private: 1,
+		})
+}
+
+func TestMarshalValue(t *testing.T) {
+	v, err := Parse([]byte(` { "a" : [ 1.50, 12345678901234567890, "x\ty" ] , "b" : { } , "c" : null } `))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testMarshal(t, `{"a":[1.50,12345678901234567890,"x\ty"],"b":{},"c":null}`, v)
+	testMarshal(t, `{"v":[true,false]}`, map[string]*Value{"v": mustParse(t, "[true, false]")})
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+	_, err := Marshal(math.NaN())
+	_, ok := err.(*UnsupportedValueError)
+	assertTrue(t, ok)
+	_, err = Marshal(make(chan int))
+	_, ok = err.(*UnsupportedTypeError)
+	assertTrue(t, ok)
+	_, err = Marshal(map[float64]int{1: 1})
+	_, ok = err.(*UnsupportedTypeError)
+	assertTrue(t, ok)
+}
+
+func mustParse(t *testing.T, s string) *Value {
+	t.Helper()
+	v, err := Parse([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// celsius 以值接收者实现 Marshaler，输出带空白以检验压缩
+type celsius float64
+
+func (c celsius) MarshalJSON() ([]byte, error) {
+	return []byte(`{ "c" : ` + strconv.FormatFloat(float64(c), 'f', -1, 64) + ` }`), nil
+}
+
+// point 以指针接收者实现 Marshaler，只在可寻址时生效
+type point struct{ X, Y int }
+
+func (p *point) MarshalJSON() ([]byte, error) {
+	return []byte(`[` + strconv.Itoa(p.X) + `,` + strconv.Itoa(p.Y) + `]`), nil
+}
+
+type badMarshaler struct{ out string }
+
+func (b badMarshaler) MarshalJSON() ([]byte, error) {
+	if b.out == "" {
+		return nil, errors.New("boom")
+	}
+	return []byte(b.out), nil
+}
+
+func TestMarshaler(t *testing.T) {
+	testMarshal(t, `{"c":21.5}`, celsius(21.5))
+	testMarshal(t, `[{"c":1},null]`, []interface{}{celsius(1), (*point)(nil)})
+	testMarshal(t, `[1,2]`, &point{1, 2})
+	// 不可寻址的值不能调用指针接收者的方法
+	testMarshal(t, `{"X":1,"Y":2}`, point{1, 2})
+	testMarshal(t, `{"P":[3,4],"Q":[5,6],"C":{"c":0}}`, &struct {
+		P point
+		Q *point
+		C *celsius
+	}{P: point{3, 4}, Q: &point{5, 6}, C: new(celsius)})
+}
+
+func TestMarshalerError(t *testing.T) {
+	_, err := Marshal(badMarshaler{})
+	me, ok := err.(*MarshalerError)
+	assertTrue(t, ok && me.Type == reflect.TypeOf(badMarshaler{}))
+	assertEqual(t, "json: error calling MarshalJSON for type json.badMarshaler: boom", err.Error())
+
+	_, err = Marshal([]badMarshaler{{out: `{"a":}`}})
+	me, ok = err.(*MarshalerError)
+	assertTrue(t, ok)
+	var se *SyntaxError
+	assertTrue(t, errors.As(err, &se))
+}
+
+// userID 以值接收者实现 TextMarshaler，以指针接收者实现 TextUnmarshaler
+type userID struct{ n int }
+
+func (id userID) MarshalText() ([]byte, error) {
+	if id.n < 0 {
+		return nil, errors.New("negative id")
+	}
+	return []byte("u-" + strconv.Itoa(id.n)), nil
+}
+
+func (id *userID) UnmarshalText(b []byte) error {
+	s, ok := strings.CutPrefix(string(b), "u-")
+	if !ok {
+		return errors.New("bad id " + string(b))
+	}
+	n, err := strconv.Atoi(s)
+	id.n = n
+	return err
+}
+
+func TestMarshalText(t *testing.T) {
+	testMarshal(t, `"u-7"`, userID{7})
+	testMarshal(t, `["10.0.0.1","::1"]`, []net.IP{net.IPv4(10, 0, 0, 1), net.IPv6loopback})
+	testMarshal(t, `{"ID":"u-1","P":null}`, struct {
+		ID userID
+		P  *userID
+	}{ID: userID{1}})
+
+	_, err := Marshal(userID{-1})
+	assertEqual(t, "json: error calling MarshalText for type json.userID: negative id", err.Error())
+}
+
+func TestMarshalMapKey(t *testing.T) {
+	testMarshal(t, `{"-1":"a","10":"c","2":"b"}`, map[int]string{2: "b", -1: "a", 10: "c"})
+	testMarshal(t, `{"18446744073709551615":true}`, map[uint64]bool{math.MaxUint64: true})
+	testMarshal(t, `{"u-1":1,"u-2":2}`, map[userID]int{{2}: 2, {1}: 1})
+	testMarshal(t, `{"10.0.0.1":1}`, map[string]int{"10.0.0.1": 1})
+
+	type named string
+	testMarshal(t, `{"k":1}`, map[named]int{"k": 1})
+}
+
+type Timestamps struct {
+	Created int `json:"created"`
+	Updated int `json:"updated,omitempty"`
+}
+
+type Metadata struct {
+	ID   string `json:"id"`
+	Name string // 与外层的 Name 冲突，层级浅的优先
+	Kind string
+	Note string
+}
+
+type Labels struct {
+	Kind string // 与 Metadata.Kind 同层且都没有 tag，两者都被忽略
+	Note string `json:"Note"`
+}
+
+type auditInfo struct {
+	By string `json:"by"`
+}
+
+type embedTag string
+
+type embedModel struct {
+	Timestamps
+	*Metadata
+	Labels
+	auditInfo
+	embedTag
+	Name   string
+	Nested Timestamps `json:"nested"`
+}
+
+func TestMarshalEmbedded(t *testing.T) {
+	m := embedModel{
+		Timestamps: Timestamps{Created: 1},
+		Metadata:   &Metadata{ID: "m1", Name: "hidden", Kind: "k1", Note: "n1"},
+		Labels:     Labels{Kind: "k2", Note: "n2"},
+		auditInfo:  auditInfo{By: "bob"},
+		embedTag:   "t",
+		Name:       "outer",
+		Nested:     Timestamps{Created: 2, Updated: 3},
+	}
+	testMarshal(t, `{"created":1,"id":"m1","Note":"n2","by":"bob","Name":"outer","nested":{"created":2,"updated":3}}`, m)
+
+	// nil 的嵌入指针中的字段被跳过
+	m.Metadata = nil
+	testMarshal(t, `{"created":1,"Note":"n2","by":"bob","Name":"outer","nested":{"created":2,"updated":3}}`, &m)
+}
+
+func TestTypeFieldsEmbedded(t *testing.T) {
+	var names []string
+	for _, f := range typeFields(reflect.TypeOf(embedModel{})) {
+		names = append(names, f.name)
+	}
+	assertEqual(t, []string{"created", "updated", "id", "Note", "by", "Name", "nested"}, names)
+
+	// 同一类型在同一层被嵌入两次时，其字段都被忽略
+	type twice struct {
+		Timestamps
+		Other struct{ Timestamps }
+	}
+	type dup struct {
+		A struct{ X int }
+		twice
+		Y int
+	}
+	names = names[:0]
+	for _, f := range typeFields(reflect.TypeOf(dup{})) {
+		names = append(names, f.name)
+	}
+	assertEqual(t, []string{"A", "created", "updated", "Other", "Y"}, names)
+
+	// 同一层级有 tag 的字段优先
+	type tagged struct {
+		Kind string `json:"Note"`
+	}
+	type prefer struct {
+		Metadata
+		tagged
+	}
+	testMarshal(t, `{"id":"","Name":"","Kind":"","Note":"t"}`, prefer{Metadata{Note: "m"}, tagged{"t"}})
+}
@@ -0,0 +1,80 @@
+package json
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Decode parses data and decodes it into a new value of type T,
+// following the same rules as Unmarshal.
+func Decode[T any](data []byte) (T, error) {
+	var x T
+	err := Unmarshal(data, &x)
+	return x, err
+}
+
+// DecodeValue decodes the parsed value v into a new value of type T,
+// following the same rules as Unmarshal.
+func DecodeValue[T any](v *Value) (T, error) {
+	var x T
+	err := unmarshalValue(v, &x)
+	return x, err
+}
+
+// Get decodes the value found at path below v into a new value of type
+// T. The path is a JSON Pointer (RFC 6901) relative to v, such as
+// "/items/0/name"; the empty path refers to v itself. When an object
+// has the same member more than once, the last one is used, as in
+// Unmarshal.
+func Get[T any](v *Value, path string) (T, error) {
+	e, err := v.lookup(path)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return DecodeValue[T](e)
+}
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// lookup 按 JSON Pointer 查找 v 下的值
+func (v *jsonValue) lookup(path string) (*jsonValue, error) {
+	if path == "" {
+		return v, nil
+	}
+	if path[0] != '/' {
+		return nil, v.error("json: invalid pointer " + strconv.Quote(path))
+	}
+	rest := path[1:]
+	for {
+		token, next, more := strings.Cut(rest, "/")
+		key := pointerUnescaper.Replace(token)
+		if err := v.load(); err != nil {
+			return nil, err
+		}
+		found := false
+		switch v.getValueType() {
+		case ValueArray:
+			// 下标不能有前导 0 和符号
+			i, err := strconv.Atoi(key)
+			if err == nil && i >= 0 && i < v.getArrayLen() && (key == "0" || key[0] != '0' && key[0] != '+') {
+				v, found = v.array.values[i], true
+			}
+		case ValueObject:
+			for i := v.getObjectSize() - 1; i >= 0; i-- {
+				if string(v.object.keys[i].s) == key {
+					v, found = v.object.values[i], true
+					break
+				}
+			}
+		}
+		if !found {
+			end := len(path) - len(rest) + len(token)
+			return nil, v.error("json: " + strconv.Quote(path[:end]) + " not found")
+		}
+		if !more {
+			return v, nil
+		}
+		rest = next
+	}
+}
@@ -0,0 +1,114 @@
+package json
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	type point struct{ X, Y int }
+	p, err := Decode[point]([]byte(`{"X":1,"Y":2}`))
+	assertTrue(t, err == nil)
+	assertEqual(t, point{1, 2}, p)
+
+	m, err := Decode[map[string][]int]([]byte(`{"a":[1,2]}`))
+	assertTrue(t, err == nil)
+	assertEqual(t, []int{1, 2}, m["a"])
+
+	_, err = Decode[int]([]byte(`"x"`))
+	var te *UnmarshalTypeError
+	assertTrue(t, errors.As(err, &te))
+	_, err = Decode[int]([]byte(`[`))
+	var se *SyntaxError
+	assertTrue(t, errors.As(err, &se))
+
+	n, err := DecodeValue[float64](mustParse(t, `1.5`))
+	assertTrue(t, err == nil && n == 1.5)
+}
+
+func TestGet(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		data := []byte(`{"items":[{"name":"a","tags":["x"]},{"name":"b"}],"a/b":{"~":true},"dup":1,"dup":2,"":0}`)
+		var v *Value
+		var err error
+		if lazy {
+			v, err = parseLazyJson(t, data)
+		} else {
+			v, err = Parse(data)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		name, err := Get[string](v, "/items/1/name")
+		assertTrue(t, err == nil)
+		assertEqual(t, "b", name)
+		tags, err := Get[[]string](v, "/items/0/tags")
+		assertTrue(t, err == nil)
+		assertEqual(t, []string{"x"}, tags)
+		ok, err := Get[bool](v, "/a~1b/~0")
+		assertTrue(t, err == nil && ok)
+		dup, err := Get[int](v, "/dup")
+		assertTrue(t, err == nil && dup == 2)
+		zero, err := Get[int](v, "/")
+		assertTrue(t, err == nil && zero == 0)
+		all, err := Get[map[string]interface{}](v, "")
+		assertTrue(t, err == nil && len(all) == 4)
+
+		_, err = Get[string](v, "/items/2/name")
+		assertEqual(t, `json: "/items/2" not found`, err.Error())
+		_, err = Get[string](v, "/items/01")
+		assertEqual(t, `json: "/items/01" not found`, err.Error())
+		_, err = Get[string](v, "/items/-")
+		assertEqual(t, `json: "/items/-" not found`, err.Error())
+		_, err = Get[string](v, "/dup/x")
+		assertEqual(t, `json: "/dup/x" not found`, err.Error())
+		_, err = Get[string](v, "items")
+		assertEqual(t, `json: invalid pointer "items"`, err.Error())
+		_, err = Get[int](v, "/items/0/name")
+		var te *UnmarshalTypeError
+		assertTrue(t, errors.As(err, &te))
+	}
+}
+
+func TestDecodeValueWithoutText(t *testing.T) {
+	// JSON5 的数字只保存了 float64
+	v, err := ParseJSON5([]byte(`{a: 1, b: 0x10, c: +2, d: -.5, e: 1.5}`))
+	assertTrue(t, err == nil)
+	s, err := DecodeValue[struct {
+		A int
+		B uint8
+		C int64
+		D float64
+	}](v)
+	assertTrue(t, err == nil)
+	assertEqual(t, 1, s.A)
+	assertEqual(t, uint8(16), s.B)
+	assertEqual(t, int64(2), s.C)
+	assertEqual(t, -0.5, s.D)
+	_, err = Get[int](v, "/e")
+	var te *UnmarshalTypeError
+	assertTrue(t, errors.As(err, &te))
+	_, err = Get[int8](v, "/b")
+	assertTrue(t, err == nil)
+	_, err = Get[uint](v, "/d")
+	assertTrue(t, errors.As(err, &te))
+
+	v, err = ParseJSONC([]byte("{\"n\": 42, // c\n}"), nil)
+	assertTrue(t, err == nil)
+	n, err := Get[int](v, "/n")
+	assertTrue(t, err == nil)
+	assertEqual(t, 42, n)
+
+	// 查询构造的数字
+	q, err := ParseQuery("length")
+	assertTrue(t, err == nil)
+	v, _ = Parse([]byte(`[1, 2, 3]`))
+	for r, err := range q.Run(v) {
+		assertTrue(t, err == nil)
+		n, err := DecodeValue[int](r)
+		assertTrue(t, err == nil)
+		assertEqual(t, 3, n)
+	}
+	_, err = DecodeValue[int](newNumberValue(1e20))
+	assertTrue(t, errors.As(err, &te))
+}
@@ -0,0 +1,96 @@
+package json
+
+import "bytes"
+
+// Valid reports whether data is a valid JSON encoding.
+func Valid(data []byte) bool {
+	_, err := Parse(data)
+	return err == nil
+}
+
+// Compact appends to dst the JSON-encoded src with insignificant
+// whitespace removed. Numbers keep their original text.
+func Compact(dst *bytes.Buffer, src []byte) error {
+	v, err := Parse(src)
+	if err != nil {
+		return err
+	}
+	b, err := appendValue(dst.AvailableBuffer(), v)
+	if err != nil {
+		return err
+	}
+	dst.Write(b)
+	return nil
+}
+
+// Indent appends to dst an indented form of the JSON-encoded src.
+// Each element in a JSON object or array begins on a new line
+// beginning with prefix followed by one or more copies of indent
+// according to the nesting depth. Empty objects and arrays stay on
+// one line.
+func Indent(dst *bytes.Buffer, src []byte, prefix, indent string) error {
+	v, err := Parse(src)
+	if err != nil {
+		return err
+	}
+	b, err := appendIndent(dst.AvailableBuffer(), v, prefix, indent, 0)
+	if err != nil {
+		return err
+	}
+	dst.Write(b)
+	return nil
+}
+
+func appendNewline(dst []byte, prefix, indent string, depth int) []byte {
+	dst = append(dst, '\n')
+	dst = append(dst, prefix...)
+	for i := 0; i < depth; i++ {
+		dst = append(dst, indent...)
+	}
+	return dst
+}
+
+// appendIndent 与 appendValue 相同，只是在数组元素和对象成员之间插入换行和缩进
+func appendIndent(dst []byte, v *jsonValue, prefix, indent string, depth int) ([]byte, error) {
+	var err error
+	switch v.getValueType() {
+	case ValueArray:
+		if v.getArrayLen() == 0 {
+			return append(dst, '[', ']'), nil
+		}
+		dst = append(dst, '[')
+		for i, e := range v.Elements() {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendNewline(dst, prefix, indent, depth+1)
+			if dst, err = appendIndent(dst, e, prefix, indent, depth+1); err != nil {
+				return dst, err
+			}
+		}
+		dst = appendNewline(dst, prefix, indent, depth)
+		return append(dst, ']'), nil
+	case ValueObject:
+		if v.getObjectSize() == 0 {
+			return append(dst, '{', '}'), nil
+		}
+		dst = append(dst, '{')
+		first := true
+		for k, e := range v.Members() {
+			if !first {
+				dst = append(dst, ',')
+			}
+			first = false
+			dst = appendNewline(dst, prefix, indent, depth+1)
+			dst = appendString(dst, k)
+			dst = append(dst, ':', ' ')
+			if dst, err = appendIndent(dst, e, prefix, indent, depth+1); err != nil {
+				return dst, err
+			}
+		}
+		dst = appendNewline(dst, prefix, indent, depth)
+		return append(dst, '}'), nil
+	default:
+		return appendValue(dst, v)
+	}
+}
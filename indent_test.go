@@ -0,0 +1,37 @@
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	assertTrue(t, Valid([]byte(` {"a":[1,2]} `)))
+	assertFalse(t, Valid([]byte(`{"a":[1,2}`)))
+	assertFalse(t, Valid([]byte(``)))
+}
+
+func TestCompact(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("x")
+	err := Compact(&buf, []byte(" { \"a\" : [ 1.50 , -0 , 1e40 ] ,\n \"b\" : \"\\u0041\" } "))
+	assertTrue(t, err == nil)
+	assertEqual(t, `x{"a":[1.50,-0,1e40],"b":"A"}`, buf.String())
+
+	buf.Reset()
+	err = Compact(&buf, []byte(`[1,]`))
+	assertTrue(t, err != nil)
+	assertEqual(t, 0, buf.Len())
+}
+
+func TestIndent(t *testing.T) {
+	var buf bytes.Buffer
+	err := Indent(&buf, []byte(`{"a":[1,{"b":null},[],{}],"c":"d"}`), ">", "  ")
+	assertTrue(t, err == nil)
+	expect := "{\n>  \"a\": [\n>    1,\n>    {\n>      \"b\": null\n>    },\n>    [],\n>    {}\n>  ],\n>  \"c\": \"d\"\n>}"
+	assertEqual(t, expect, buf.String())
+
+	buf.Reset()
+	assertTrue(t, Indent(&buf, []byte(` 3 `), "", "\t") == nil)
+	assertEqual(t, "3", buf.String())
+}
@@ -0,0 +1,85 @@
+package json
+
+import (
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// Elements returns an iterator over the index and value of each array element.
+// It yields nothing if v is not an array. If the elements of a Value returned
+// by ParseLazy cannot be built, the iteration stops early and v.Err reports
+// the error.
+func (v *jsonValue) Elements() iter.Seq2[int, *Value] {
+	return func(yield func(int, *Value) bool) {
+		if v.valueType != ValueArray {
+			return
+		}
+		for i := 0; i < v.getArrayLen(); i++ {
+			e, err := v.getArrayElem(i)
+			if err != nil || !yield(i, e) {
+				return
+			}
+		}
+	}
+}
+
+// Members returns an iterator over the key and value of each object member,
+// in document order. It yields nothing if v is not an object. Like Elements,
+// it stops early if v.Err is not nil.
+func (v *jsonValue) Members() iter.Seq2[string, *Value] {
+	return func(yield func(string, *Value) bool) {
+		if v.valueType != ValueObject {
+			return
+		}
+		for i := 0; i < v.getObjectSize(); i++ {
+			k, err := v.getObjectKey(i)
+			if err != nil {
+				return
+			}
+			e, err := v.getObjectValue(i)
+			if err != nil || !yield(string(k.s), e) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over v and all of its descendants in depth-first
+// order. Each value is paired with its JSON Pointer (RFC 6901) relative to v;
+// the pointer of v itself is "". A value whose Err is not nil is yielded but
+// its descendants are not, so callers that use ParseLazy should check Err on
+// each yielded value.
+func (v *jsonValue) All() iter.Seq2[string, *Value] {
+	return func(yield func(string, *Value) bool) {
+		v.all("", yield)
+	}
+}
+
+func (v *jsonValue) all(path string, yield func(string, *Value) bool) bool {
+	if !yield(path, v) {
+		return false
+	}
+	switch v.valueType {
+	case ValueArray:
+		for i, e := range v.Elements() {
+			if !e.all(path+"/"+strconv.Itoa(i), yield) {
+				return false
+			}
+		}
+	case ValueObject:
+		for k, e := range v.Members() {
+			if !e.all(path+"/"+escapePointer(k), yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// escapePointer 按 RFC 6901 转义 JSON Pointer 中的 ~ 和 /
+func escapePointer(key string) string {
+	return pointerEscaper.Replace(key)
+}
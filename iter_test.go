@@ -0,0 +1,88 @@
+package json
+
+import "testing"
+
+func TestElements(t *testing.T) {
+	v, err := Parse([]byte(`[1, "a", true]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []ValueType
+	for i, e := range v.Elements() {
+		assertEqual(t, len(types), i)
+		types = append(types, e.getValueType())
+	}
+	assertEqual(t, []ValueType{ValueNumber, ValueString, ValueTrue}, types)
+
+	// 提前退出
+	count := 0
+	for range v.Elements() {
+		count++
+		break
+	}
+	assertEqual(t, 1, count)
+}
+
+func TestMembers(t *testing.T) {
+	v, err := Parse([]byte(`{"b": 1, "a": 2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	var sum float64
+	for k, e := range v.Members() {
+		keys = append(keys, k)
+		n, _ := e.getNumber()
+		sum += n
+	}
+	assertEqual(t, []string{"b", "a"}, keys)
+	assertEqual(t, 3.0, sum)
+
+	for range v.Elements() {
+		t.Error("object should have no elements")
+	}
+}
+
+func TestAll(t *testing.T) {
+	v, err := Parse([]byte(`{"a": [1, {"b/c": null}], "d~": {}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for p := range v.All() {
+		paths = append(paths, p)
+	}
+	assertEqual(t, []string{"", "/a", "/a/0", "/a/1", "/a/1/b~1c", "/d~0"}, paths)
+
+	paths = paths[:0]
+	for p := range v.All() {
+		if p == "/a/0" {
+			break
+		}
+		paths = append(paths, p)
+	}
+	assertEqual(t, []string{"", "/a"}, paths)
+}
+
+func TestAllLazy(t *testing.T) {
+	v, err := parseLazyJson(t, []byte(`[[1], {"a": [2]}]`))
+	if err != nil {
+		return
+	}
+	count := 0
+	for range v.All() {
+		count++
+	}
+	assertEqual(t, 6, count)
+}
+
+func TestMembersLazyError(t *testing.T) {
+	v, err := parseLazyJson(t, []byte(`{"a": 1e400}`))
+	if err != nil {
+		return
+	}
+	for range v.Members() {
+		t.Error("broken object should yield no members")
+	}
+	assertTrue(t, v.Err() != nil)
+}
@@ -0,0 +1,239 @@
+package json
+
+import (
+	"bytes"
+	"math"
+	"unicode"
+	"unicode/utf8"
+)
+
+// JSON5 扩展（https://spec.json5.org/）：
+// 注释、尾随逗号、单引号字符串、标识符形式的 key、十六进制数字、
+// 省略整数或小数部分的数字、正号、Infinity/NaN 以及字符串续行。
+
+// ParseJSON5 parses data as JSON5 and returns the root Value.
+// Parse keeps accepting strict RFC 8259 JSON only.
+func ParseJSON5(data []byte) (*Value, error) {
+	d := &jsonParse{json5: true}
+	d.init(data)
+	v, err := d.parser()
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// skipExtraSpace 跳过 JSON5/JSONC 额外允许的空白和注释，返回是否前进
+func (d *jsonParse) skipExtraSpace() bool {
+	start := d.off
+	for {
+		c := d.pop()
+		switch {
+		case d.json5 && (c == '\v' || c == '\f'):
+			d.off++
+		case d.json5 && c >= utf8.RuneSelf && isSpace5(d.data[d.off:]) > 0:
+			d.off += isSpace5(d.data[d.off:])
+		case c == '/' && d.skipComment():
+		default:
+			return d.off > start
+		}
+	}
+}
+
+// trailingComma 在逗号之后判断是否紧跟结束符 end（JSON5、JSONC 允许尾随逗号）
+func (d *jsonParse) trailingComma(end byte) bool {
+	if !d.json5 && !d.jsonc {
+		return false
+	}
+	d.skipWhiteSpace()
+	return d.pop() == end
+}
+
+// parseKey 解析对象的 key，JSON5 下允许不加引号的标识符
+func (d *jsonParse) parseKey(v *jsonValue) error {
+	if !d.json5 || identifierChar(d.data[d.off:], true) == 0 {
+		return d.parseString(v)
+	}
+	start := d.off
+	for n := identifierChar(d.data[d.off:], true); n > 0; n = identifierChar(d.data[d.off:], false) {
+		d.off += n
+	}
+	v.s = append([]byte(nil), d.data[start:d.off]...)
+	v.valueType = ValueString
+	return nil
+}
+
+// isSpace5 返回 data 开头的非 ASCII 空白字符的字节数，不是空白时返回 0。
+// JSON5 的空白包括 U+00A0、U+FEFF、行分隔符 U+2028、段分隔符 U+2029 以及 Zs 类字符
+func isSpace5(data []byte) int {
+	r, n := utf8.DecodeRune(data)
+	if r == '\ufeff' || r == '\u2028' || r == '\u2029' || unicode.Is(unicode.Zs, r) {
+		return n
+	}
+	return 0
+}
+
+// identifierChar 返回 data 开头的标识符字符的字节数，不是标识符字符时返回 0。
+// 按 ECMAScript 5.1 的 IdentifierName：开头是 Unicode 字母、$ 或 _，
+// 之后还可以是数字、组合符号、连接符以及 ZWNJ/ZWJ
+func identifierChar(data []byte, start bool) int {
+	if len(data) == 0 {
+		return 0
+	}
+	if c := data[0]; c < utf8.RuneSelf {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$' || !start && isDigit(c) {
+			return 1
+		}
+		return 0
+	}
+	r, n := utf8.DecodeRune(data)
+	if unicode.In(r, unicode.L, unicode.Nl) {
+		return n
+	}
+	if !start && (unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc) || r == '\u200c' || r == '\u200d') {
+		return n
+	}
+	return 0
+}
+
+// parseEscape5 处理 JSON5 额外的转义：\' \v \0 \xHH、续行以及任意字符转义为自身
+func (d *jsonParse) parseEscape5(c byte, buf *bytes.Buffer) error {
+	switch c {
+	case 'v':
+		buf.WriteByte('\v')
+	case '0':
+		if isDigit(d.peekByte(1)) {
+			return d.error(c, "invalid_string_escape")
+		}
+		buf.WriteByte(0)
+	case 'x':
+		var r rune
+		for i := 0; i < 2; i++ {
+			h := d.next()
+			n, ok := hexValue(h)
+			if !ok {
+				return d.error(h, "invalid_string_hex")
+			}
+			r = r<<4 | n
+		}
+		buf.WriteRune(r)
+	case '\r':
+		if d.peekByte(1) == '\n' {
+			d.off++
+		}
+	case '\n':
+	case 0:
+		return d.error(c, "miss quotation mark")
+	default:
+		// U+2028、U+2029 续行
+		if bytes.HasPrefix(d.data[d.off:], []byte("\u2028")) || bytes.HasPrefix(d.data[d.off:], []byte("\u2029")) {
+			d.off += 2
+			break
+		}
+		if isDigit(c) || c == 'u' {
+			return d.error(c, "invalid_string_escape")
+		}
+		buf.WriteByte(c)
+	}
+	return nil
+}
+
+// peekByte 返回当前位置之后第 n 个字节
+func (d *jsonParse) peekByte(n int) byte {
+	if d.off+n > len(d.data)-1 {
+		return 0
+	}
+	return d.data[d.off+n]
+}
+
+func hexValue(c byte) (rune, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return rune(c - '0'), true
+	case c >= 'A' && c <= 'F':
+		return rune(c - 'A' + 10), true
+	case c >= 'a' && c <= 'f':
+		return rune(c - 'a' + 10), true
+	}
+	return 0, false
+}
+
+// parseNumber5 解析 JSON5 数字：正负号、Infinity、NaN、十六进制以及 .5、5. 形式的小数
+func (d *jsonParse) parseNumber5(v *jsonValue) error {
+	c := d.pop()
+	sign := 1.0
+	if c == '+' || c == '-' {
+		if c == '-' {
+			sign = -1
+		}
+		c = d.next()
+	}
+	start := d.off
+	switch {
+	case c == 'I' || c == 'N':
+		literal, n := "Infinity", math.Inf(1)
+		if c == 'N' {
+			literal, n = "NaN", math.NaN()
+		}
+		for i := 0; i < len(literal); i++ {
+			if c = d.pop(); c != literal[i] {
+				return d.error(c, "number syntax invalid")
+			}
+			d.off++
+		}
+		v.n = sign * n
+	case c == '0' && (d.peekByte(1) == 'x' || d.peekByte(1) == 'X'):
+		d.off++
+		n := 0.0
+		digits := 0
+		for c = d.next(); ; c = d.next() {
+			h, ok := hexValue(c)
+			if !ok {
+				break
+			}
+			n = n*16 + float64(h)
+			digits++
+		}
+		if digits == 0 {
+			return d.error(c, "number syntax invalid")
+		}
+		v.n = sign * n
+	default:
+		digits := 0
+		if c == '0' {
+			c = d.next()
+			digits++
+		} else {
+			for ; isDigit(c); c = d.next() {
+				digits++
+			}
+		}
+		if c == '.' {
+			for c = d.next(); isDigit(c); c = d.next() {
+				digits++
+			}
+		}
+		if digits == 0 {
+			return d.error(c, "number syntax invalid")
+		}
+		if c == 'e' || c == 'E' {
+			c = d.next()
+			if c == '-' || c == '+' {
+				c = d.next()
+			}
+			if !isDigit(c) {
+				return d.error(c, "number syntax invalid")
+			}
+			for isDigit(c) {
+				c = d.next()
+			}
+		}
+		n, err := convertNumber(string(d.data[start:d.off]))
+		if err != nil {
+			return err
+		}
+		v.n = sign * n
+	}
+	v.valueType = ValueNumber
+	return nil
+}
@@ -0,0 +1,137 @@
+package json
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func parseJson5(t *testing.T, data []byte) (*jsonValue, error) {
+	t.Helper()
+	v, err := ParseJSON5(data)
+	if err != nil {
+		t.Errorf("ParseJSON5 %s error %s", string(data), err.Error())
+	}
+	return v, err
+}
+
+func testJson5Error(t *testing.T, data []byte, msg string) {
+	t.Helper()
+	_, err := ParseJSON5(data)
+	if err == nil {
+		t.Errorf("data %s should be error, but pass", data)
+		return
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Errorf("Data %s Should be error is [%s], but error is [%s]", data, msg, err.Error())
+	}
+}
+
+func testJson5Number(t *testing.T, expect float64, source string) {
+	t.Helper()
+	v, err := parseJson5(t, []byte(source))
+	if err != nil {
+		return
+	}
+	n, _ := v.getNumber()
+	assertEqual(t, expect, n)
+}
+
+func testJson5String(t *testing.T, expect, source string) {
+	t.Helper()
+	v, err := parseJson5(t, []byte(source))
+	if err != nil {
+		return
+	}
+	s, _ := v.getString()
+	assertEqual(t, expect, s)
+}
+
+func TestJson5Number(t *testing.T) {
+	testJson5Number(t, 255, "0xFF")
+	testJson5Number(t, -255, "-0xff")
+	testJson5Number(t, 0.5, ".5")
+	testJson5Number(t, 5, "5.")
+	testJson5Number(t, 1, "+1")
+	testJson5Number(t, 1.5e3, "+1.5e3")
+	testJson5Number(t, math.Inf(1), "Infinity")
+	testJson5Number(t, math.Inf(-1), "-Infinity")
+
+	v, err := parseJson5(t, []byte("NaN"))
+	if err == nil {
+		n, _ := v.getNumber()
+		assertTrue(t, math.IsNaN(n))
+	}
+}
+
+func TestJson5String(t *testing.T) {
+	testJson5String(t, `a"b`, `'a"b'`)
+	testJson5String(t, "it's", `'it\'s'`)
+	testJson5String(t, "line one line two", "'line one \\\nline two'")
+	testJson5String(t, "ab", "\"a\\\r\nb\"")
+	testJson5String(t, "A\v\x00", `"\x41\v\0"`)
+	testJson5String(t, "a", `"\a"`)
+}
+
+func TestJson5Object(t *testing.T) {
+	v, err := parseJson5(t, []byte(`// config
+{
+	unquoted: 'and you can quote me on that',
+	/* block
+	   comment */
+	$key_1: [1, 2, 3,],
+	"quoted": {nested: null,},
+}`))
+	if err != nil {
+		return
+	}
+	assertEqual(t, 3, v.getObjectSize())
+	var keys []string
+	for k := range v.Members() {
+		keys = append(keys, k)
+	}
+	assertEqual(t, []string{"unquoted", "$key_1", "quoted"}, keys)
+	a, _ := v.getObjectValue(1)
+	assertEqual(t, 3, a.getArrayLen())
+	o, _ := v.getObjectValue(2)
+	assertEqual(t, 1, o.getObjectSize())
+}
+
+func TestJson5Unicode(t *testing.T) {
+	v, err := parseJson5(t, []byte("{\u2028ключ: 1,\u2029café_\u0301\u200d: 2,\u3000ǅ٣: 3}"))
+	if err != nil {
+		return
+	}
+	var keys []string
+	for k := range v.Members() {
+		keys = append(keys, k)
+	}
+	assertEqual(t, []string{"ключ", "café_\u0301\u200d", "ǅ٣"}, keys)
+
+	// 标识符只能以字母、$ 或 _ 开头
+	testJson5Error(t, []byte("{€: 1}"), "miss key")
+	testJson5Error(t, []byte("{\u0301a: 1}"), "miss key")
+	testJson5Error(t, []byte("{\xffa: 1}"), "miss key")
+	testJson5Error(t, []byte("{a€: 1}"), "miss colon")
+}
+
+func TestJson5Invalid(t *testing.T) {
+	testJson5Error(t, []byte("[1,,]"), "invalid character")
+	testJson5Error(t, []byte("{,}"), "miss key")
+	testJson5Error(t, []byte("0x"), "number syntax invalid")
+	testJson5Error(t, []byte("."), "number syntax invalid")
+	testJson5Error(t, []byte("Inf"), "number syntax invalid")
+	testJson5Error(t, []byte("/* open"), "invalid character")
+	testJson5Error(t, []byte(`"\x4"`), "invalid_string_hex")
+	testJson5Error(t, []byte(`"\01"`), "invalid_string_escape")
+}
+
+func TestStrictRejectsJson5(t *testing.T) {
+	testError(t, []byte("[1,]"), "invalid character")
+	testError(t, []byte("{\"a\":1,}"), "miss key")
+	testError(t, []byte("'a'"), "number syntax invalid")
+	testError(t, []byte("{a:1}"), "miss key")
+	testError(t, []byte("// c\n1"), "number syntax invalid")
+	testError(t, []byte("Infinity"), "number syntax invalid")
+	testError(t, []byte("\"\\x41\""), "invalid_string_escape")
+}
@@ -0,0 +1,53 @@
+package json
+
+import "bytes"
+
+// A Comment is a comment found while parsing JSONC or JSON5 input.
+type Comment struct {
+	Text   string // comment text including the // or /* */ markers
+	Offset int    // byte offset of the comment in the input
+	Block  bool   // true for /* */ comments
+}
+
+// ParseJSONC parses data as JSONC (JSON with comments, as used by
+// VS Code settings and tsconfig files): comments are treated as
+// whitespace and trailing commas are allowed. If onComment is not
+// nil it is called for every comment in input order.
+func ParseJSONC(data []byte, onComment func(Comment)) (*Value, error) {
+	d := &jsonParse{jsonc: true, onComment: onComment}
+	d.init(data)
+	v, err := d.parser()
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// skipComment 跳过一个 // 或 /* */ 注释；未闭合的块注释不跳过，交给后续解析报错
+func (d *jsonParse) skipComment() bool {
+	rest := d.data[d.off:]
+	var end int
+	block := false
+	switch {
+	case bytes.HasPrefix(rest, []byte("//")):
+		end = bytes.IndexByte(rest, '\n')
+		if end < 0 {
+			end = len(rest)
+		}
+		end = len(bytes.TrimRight(rest[:end], "\r"))
+	case bytes.HasPrefix(rest, []byte("/*")):
+		end = bytes.Index(rest[2:], []byte("*/"))
+		if end < 0 {
+			return false
+		}
+		end += 4
+		block = true
+	default:
+		return false
+	}
+	if d.onComment != nil {
+		d.onComment(Comment{Text: string(rest[:end]), Offset: d.off, Block: block})
+	}
+	d.off += end
+	return true
+}
@@ -0,0 +1,50 @@
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+func testJsoncError(t *testing.T, data []byte, msg string) {
+	t.Helper()
+	_, err := ParseJSONC(data, nil)
+	if err == nil {
+		t.Errorf("data %s should be error, but pass", data)
+		return
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Errorf("Data %s Should be error is [%s], but error is [%s]", data, msg, err.Error())
+	}
+}
+
+func TestJsonc(t *testing.T) {
+	data := []byte("{\r\n\t// editor settings\r\n\t\"editor.tabSize\": 2, /* spaces */\n\t\"files.exclude\": [\"**/.git\",],\n}\n// end")
+	var comments []Comment
+	v, err := ParseJSONC(data, func(c Comment) {
+		comments = append(comments, c)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 2, v.getObjectSize())
+	a, _ := v.getObjectValue(1)
+	assertEqual(t, 1, a.getArrayLen())
+
+	assertEqual(t, []Comment{
+		{Text: "// editor settings", Offset: 4},
+		{Text: "/* spaces */", Offset: 46, Block: true},
+		{Text: "// end", Offset: 93},
+	}, comments)
+	for _, c := range comments {
+		assertEqual(t, c.Text, string(data[c.Offset:c.Offset+len(c.Text)]))
+	}
+}
+
+func TestJsoncInvalid(t *testing.T) {
+	testJsoncError(t, []byte("{a: 1}"), "miss key")
+	testJsoncError(t, []byte("['a']"), "number syntax invalid")
+	testJsoncError(t, []byte("0x10"), "unexpected end of JSON input")
+	testJsoncError(t, []byte("[1,,]"), "invalid character")
+	testJsoncError(t, []byte("[1 /* open ]"), "MISS_COMMA_OR_SQUARE_BRACKET")
+	testJsoncError(t, []byte("[1]\v"), "unexpected end of JSON input")
+}
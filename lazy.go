@@ -0,0 +1,195 @@
+package json
+
+import "fmt"
+
+// 按需解析：数组和对象在解析时只用 skipValue 找到边界并校验结构，
+// 原文保存在 jsonValue.text 中，第一次访问时才真正解析一层。
+
+// ParseLazy parses the JSON-encoded data like Parse, but only checks
+// the syntax of arrays and objects and does not build their elements
+// until they are first accessed. It is faster than Parse when only a
+// small part of a large document is used.
+//
+// The first access to an array or object of the result, through its
+// iterators, Get or DecodeValue, modifies the Value, so a Value
+// returned by ParseLazy is not safe for concurrent use, even by
+// readers only.
+func ParseLazy(data []byte) (*Value, error) {
+	d := &jsonParse{lazy: true}
+	d.init(data)
+	v, err := d.parser()
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// deferValue 跳过当前的数组或对象，记录其原文
+func (d *jsonParse) deferValue(v *jsonValue, valueType ValueType) error {
+	start := d.off
+	if err := d.skipValue(); err != nil {
+		return err
+	}
+	v.text = d.data[start:d.off]
+	v.deferred = true
+	v.valueType = valueType
+	return nil
+}
+
+// Err returns the error, if any, found while building the elements or
+// members of v. It is always nil for a Value returned by Parse. For a
+// Value returned by ParseLazy, Err first builds v if that has not been
+// done yet; Elements, Members and All stop early at a value whose Err
+// is not nil.
+func (v *jsonValue) Err() error {
+	return v.load()
+}
+
+// load 解析按需模式下尚未展开的数组或对象，子节点仍保持按需。
+// load 会修改 v，按需解析的值不能被并发读取
+func (v *jsonValue) load() error {
+	if !v.deferred {
+		return v.err
+	}
+	d := &jsonParse{lazy: true, base: v.start}
+	d.init(v.text)
+	v.deferred = false
+	if v.valueType == ValueArray {
+		v.err = d.parseArray(v)
+	} else {
+		v.err = d.parseObject(v)
+	}
+	return v.err
+}
+
+// skipValue 校验并跳过一个完整的值，不生成任何 jsonValue
+func (d *jsonParse) skipValue() error {
+	switch c := d.pop(); c {
+	case 'n':
+		return d.skipLiteral("null", ValueNull)
+	case 't':
+		return d.skipLiteral("true", ValueTrue)
+	case 'f':
+		return d.skipLiteral("false", ValueFalse)
+	case '"':
+		return d.skipString()
+	case '[':
+		d.next()
+		d.skipWhiteSpace()
+		if d.pop() == ']' {
+			d.next()
+			return nil
+		}
+		for {
+			d.skipWhiteSpace()
+			if err := d.skipValue(); err != nil {
+				return err
+			}
+			d.skipWhiteSpace()
+			c = d.pop()
+			if c == ',' {
+				d.next()
+			} else if c == ']' {
+				d.next()
+				return nil
+			} else {
+				return d.error(c, "MISS_COMMA_OR_SQUARE_BRACKET")
+			}
+		}
+	case '{':
+		d.next()
+		d.skipWhiteSpace()
+		if d.pop() == '}' {
+			d.next()
+			return nil
+		}
+		for {
+			d.skipWhiteSpace()
+			if d.pop() != '"' || d.skipString() != nil {
+				return d.error(c, "miss key")
+			}
+			d.skipWhiteSpace()
+			c = d.pop()
+			if c != ':' {
+				return d.error(c, "miss colon")
+			}
+			d.next()
+			d.skipWhiteSpace()
+			if err := d.skipValue(); err != nil {
+				return err
+			}
+			d.skipWhiteSpace()
+			c = d.pop()
+			if c == ',' {
+				d.next()
+			} else if c == '}' {
+				d.next()
+				return nil
+			} else {
+				return d.error(c, "miss comma or curly bracket")
+			}
+		}
+	default:
+		return d.scanNumber()
+	}
+}
+
+func (d *jsonParse) skipLiteral(literal string, valueType ValueType) error {
+	for i := 0; i < len(literal); i++ {
+		if c := d.pop(); c != literal[i] {
+			return d.error(c, fmt.Sprintf("parseJson type %d error", valueType))
+		}
+		d.off++
+	}
+	return nil
+}
+
+// skipString 校验字符串的转义和控制字符，不拷贝内容
+func (d *jsonParse) skipString() error {
+	c := d.next()
+	for {
+		switch c {
+		case '"':
+			d.next()
+			return nil
+		case '\\':
+			c = d.next()
+			switch c {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			case 'u':
+				r, err := d.parseHex4()
+				if err != nil {
+					return err
+				}
+				if r >= 0xD800 && r <= 0xDFFF {
+					if d.next() != '\\' || d.next() != 'u' {
+						return d.error(d.pop(), "invalid_unicode_surrogate")
+					}
+					r2, err := d.parseHex4()
+					if err != nil {
+						return err
+					}
+					if r2 < 0xDC00 || r2 > 0xDFFF {
+						return d.error(d.pop(), "invalid_unicode_surrogate")
+					}
+				}
+			default:
+				return d.error(c, "invalid_string_escape")
+			}
+			c = d.next()
+		case 0:
+			return d.error(c, "miss quotation mark")
+		default:
+			if c < 0x20 {
+				return d.error(c, "invalid string char")
+			}
+			// 连续跳过不需要检查的字符
+			i := d.off + 1
+			for i < len(d.data) && d.data[i] >= 0x20 && d.data[i] != '"' && d.data[i] != '\\' {
+				i++
+			}
+			d.off = i
+			c = d.pop()
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+func parseLazyJson(t *testing.T, data []byte) (*jsonValue, error) {
+	t.Helper()
+	value, err := ParseLazy(data)
+	if err != nil {
+		t.Errorf("parseLazyJson %s error %s", string(data), err.Error())
+		return nil, err
+	}
+	return value, nil
+}
+
+func testSkipError(t *testing.T, data []byte, msg string) {
+	t.Helper()
+	decode := new(jsonParse)
+	decode.init(data)
+	err := decode.skipValue()
+	if err == nil {
+		t.Errorf("data %s should be error, but pass", data)
+		return
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Errorf("Data %s Should be error is [%s], but error is [%s]", data, msg, err.Error())
+	}
+}
+
+func TestLazyDefersContainers(t *testing.T) {
+	v, err := parseLazyJson(t, []byte(`{"a": [1, 2, {"b": "c"}], "d": true}`))
+	if err != nil {
+		return
+	}
+	assertTrue(t, v.getValueType() == ValueObject)
+	assertTrue(t, v.deferred)
+	assertEqual(t, 2, v.getObjectSize())
+	assertTrue(t, !v.deferred)
+
+	a, _ := v.getObjectValue(0)
+	assertTrue(t, a.getValueType() == ValueArray)
+	assertTrue(t, a.deferred)
+	assertEqual(t, 3, a.getArrayLen())
+
+	o, _ := a.getArrayElem(2)
+	assertTrue(t, o.deferred)
+	b, _ := o.getObjectValue(0)
+	s, _ := b.getString()
+	assertEqual(t, "c", s)
+
+	d, _ := v.getObjectValue(1)
+	assertTrue(t, d.getValueType() == ValueTrue)
+}
+
+func TestLazyLoadError(t *testing.T) {
+	v, err := parseLazyJson(t, []byte(`[1, 1e309]`))
+	if err != nil {
+		return
+	}
+	_, err = v.getArrayElem(0)
+	assertTrue(t, err != nil && strings.Contains(err.Error(), "number out of range"))
+}
+
+func TestLazyNestedError(t *testing.T) {
+	v, err := ParseLazy([]byte(`{"a": [1, [2, 1e400], 3], "b": true}`))
+	assertTrue(t, err == nil)
+	assertTrue(t, v.Err() == nil)
+	var paths []string
+	var broken string
+	for p, e := range v.All() {
+		paths = append(paths, p)
+		if e.Err() != nil {
+			broken = p
+			assertTrue(t, strings.Contains(e.Err().Error(), "number out of range"))
+		}
+	}
+	assertEqual(t, "/a/1", broken)
+	assertEqual(t, []string{"", "/a", "/a/0", "/a/1", "/a/2", "/b"}, paths)
+	a, _ := v.getObjectValue(0)
+	e, _ := a.getArrayElem(1)
+	assertEqual(t, 0, e.getArrayLen())
+	for range e.Elements() {
+		t.Error("broken array should yield no elements")
+	}
+}
+
+func TestParseLazy(t *testing.T) {
+	v, err := ParseLazy([]byte(`{"a": [1, 2], "b": {"c": "d"}}`))
+	assertTrue(t, err == nil)
+	s, err := Get[string](v, "/b/c")
+	assertTrue(t, err == nil)
+	assertEqual(t, "d", s)
+	n, err := DecodeValue[[]int](v.object.values[0])
+	assertTrue(t, err == nil)
+	assertEqual(t, []int{1, 2}, n)
+
+	// 语法错误在解析时就报告，不会推迟到访问时
+	v, err = ParseLazy([]byte(`{"a": [1 2]}`))
+	assertTrue(t, v == nil && err != nil && strings.Contains(err.Error(), "MISS_COMMA_OR_SQUARE_BRACKET"))
+}
+
+func TestSkipValue(t *testing.T) {
+	data := []byte(` {"a": [1, -2.5e3, "x\"\u00e9\uD834\uDD1E"], "b": {"c": null, "d": false}} , 1`)
+	decode := new(jsonParse)
+	decode.init(data)
+	decode.skipWhiteSpace()
+	if err := decode.skipValue(); err != nil {
+		t.Fatal(err)
+	}
+	decode.skipWhiteSpace()
+	assertEqual(t, byte(','), decode.pop())
+}
+
+func TestSkipInvalid(t *testing.T) {
+	testSkipError(t, []byte("nul"), "parseJson type 0 error")
+	testSkipError(t, []byte("+1"), "number syntax invalid")
+	testSkipError(t, []byte("\"abc"), "miss quotation mark")
+	testSkipError(t, []byte("\"\\x\""), "invalid_string_escape")
+	testSkipError(t, []byte("\"\\uD800\""), "invalid_unicode_surrogate")
+	testSkipError(t, []byte("[1 2"), "MISS_COMMA_OR_SQUARE_BRACKET")
+	testSkipError(t, []byte("{1:1}"), "miss key")
+	testSkipError(t, []byte("{\"a\"}"), "miss colon")
+	testSkipError(t, []byte("{\"a\": 1]"), "miss comma or curly bracket")
+}
@@ -0,0 +1,392 @@
+package json
+
+import (
+	"bytes"
+	"encoding/base64"
+	"iter"
+	"reflect"
+	"strconv"
+)
+
+// A Lexer reads a JSON document value by value, without building a
+// Value tree or using reflection to find where values go. It is the
+// runtime used by the MarshalJSON and UnmarshalJSON methods that
+// cmd/gojson-gen generates, and follows the same rules as Unmarshal.
+//
+// The first error stops the Lexer: later reads return zero values,
+// Null reports true and Members and Elements yield nothing, so
+// generated code only checks End once at the end.
+//
+// The reflect.Type arguments name the Go type being decoded into. They
+// are used for UnmarshalTypeError and, in Int, Uint and Float, to check
+// that the number fits the type, which goes through package reflect.
+type Lexer struct {
+	d         jsonParse
+	scratch   jsonValue
+	err       error
+	path      []pathElem
+	fieldErrs FieldErrors
+}
+
+// Appender is implemented by Marshaler types that can append their JSON
+// encoding to a buffer. Marshal calls AppendJSON in place of
+// MarshalJSON. It checks the appended bytes and removes white space
+// from them, as it does for the result of MarshalJSON, but in place,
+// without building a Value or copying. cmd/gojson-gen generates
+// AppendJSON methods.
+type Appender interface {
+	AppendJSON(b []byte) ([]byte, error)
+}
+
+// LexerDecoder is implemented by Unmarshaler types that can decode
+// themselves from a Lexer. When the input is strict JSON, Unmarshal
+// calls DecodeJSON in place of UnmarshalJSON, and decodes a top-level
+// LexerDecoder without building a Value tree at all. DecodeJSON must
+// consume exactly one value. cmd/gojson-gen generates DecodeJSON
+// methods.
+type LexerDecoder interface {
+	DecodeJSON(l *Lexer)
+}
+
+// NewLexer returns a Lexer that reads data.
+func NewLexer(data []byte) *Lexer {
+	l := &Lexer{}
+	l.d.init(data)
+	l.d.lazy = true
+	return l
+}
+
+// fail 记录第一个错误
+func (l *Lexer) fail(err error) {
+	if l.err == nil {
+		l.err = err
+	}
+}
+
+// read 读取下一个值，数组和对象只跳过并记录类型；出错时返回 nil
+func (l *Lexer) read() *jsonValue {
+	if l.err != nil {
+		return nil
+	}
+	d, v := &l.d, &l.scratch
+	*v = jsonValue{}
+	d.skipWhiteSpace()
+	var err error
+	switch d.pop() {
+	case 'n':
+		err = d.parseLiteral([]byte("null"), v, ValueNull)
+	case 't':
+		err = d.parseLiteral([]byte("true"), v, ValueTrue)
+	case 'f':
+		err = d.parseLiteral([]byte("false"), v, ValueFalse)
+	case '"':
+		err = d.parseString(v)
+	case '[':
+		err = d.deferValue(v, ValueArray)
+	case '{':
+		err = d.deferValue(v, ValueObject)
+	default:
+		err = d.parseNumber(v)
+	}
+	if err != nil {
+		l.fail(err)
+		return nil
+	}
+	return v
+}
+
+// expect 读取下一个值，类型不在 vts 中时记录类型错误并返回 nil
+func (l *Lexer) expect(t reflect.Type, vts ...ValueType) *jsonValue {
+	v := l.read()
+	if v == nil {
+		return nil
+	}
+	for _, vt := range vts {
+		if v.valueType == vt {
+			return v
+		}
+	}
+	l.fail(newTypeError(v, t))
+	return nil
+}
+
+// Null consumes a JSON null and reports true if the next value is
+// null. It also reports true once the Lexer has failed.
+func (l *Lexer) Null() bool {
+	if l.err != nil {
+		return true
+	}
+	l.d.skipWhiteSpace()
+	if l.d.pop() != 'n' {
+		return false
+	}
+	if err := l.d.skipLiteral("null", ValueNull); err != nil {
+		l.fail(err)
+	}
+	return true
+}
+
+// Bool reads a JSON boolean.
+func (l *Lexer) Bool(t reflect.Type) bool {
+	v := l.expect(t, ValueTrue, ValueFalse)
+	return v != nil && v.valueType == ValueTrue
+}
+
+// Int reads a JSON number into a signed integer of type t.
+func (l *Lexer) Int(t reflect.Type) int64 {
+	v := l.expect(t, ValueNumber)
+	if v == nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(string(v.s), 10, 64)
+	if err != nil || reflect.Zero(t).OverflowInt(n) {
+		l.fail(newTypeError(v, t))
+		return 0
+	}
+	return n
+}
+
+// Uint reads a JSON number into an unsigned integer of type t.
+func (l *Lexer) Uint(t reflect.Type) uint64 {
+	v := l.expect(t, ValueNumber)
+	if v == nil {
+		return 0
+	}
+	n, err := strconv.ParseUint(string(v.s), 10, 64)
+	if err != nil || reflect.Zero(t).OverflowUint(n) {
+		l.fail(newTypeError(v, t))
+		return 0
+	}
+	return n
+}
+
+// Float reads a JSON number into a floating-point number of type t.
+func (l *Lexer) Float(t reflect.Type) float64 {
+	v := l.expect(t, ValueNumber)
+	if v == nil {
+		return 0
+	}
+	if reflect.Zero(t).OverflowFloat(v.n) {
+		l.fail(newTypeError(v, t))
+		return 0
+	}
+	return v.n
+}
+
+// String reads a JSON string.
+func (l *Lexer) String(t reflect.Type) string {
+	v := l.expect(t, ValueString)
+	if v == nil {
+		return ""
+	}
+	return string(v.s)
+}
+
+// Bytes reads a base64-encoded JSON string.
+func (l *Lexer) Bytes(t reflect.Type) []byte {
+	v := l.expect(t, ValueString)
+	if v == nil {
+		return nil
+	}
+	b, err := base64.StdEncoding.DecodeString(string(v.s))
+	if err != nil {
+		l.fail(err)
+		return nil
+	}
+	return b
+}
+
+// Members returns an iterator over the member names of a JSON object
+// being decoded into type t. The loop body must consume exactly one
+// value per member, for example with Skip, and must not break out of
+// the loop.
+func (l *Lexer) Members(t reflect.Type) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		if l.err != nil {
+			return
+		}
+		d := &l.d
+		d.skipWhiteSpace()
+		if d.pop() != '{' {
+			l.expect(t, ValueObject)
+			return
+		}
+		d.next()
+		d.skipWhiteSpace()
+		if d.pop() == '}' {
+			d.next()
+			return
+		}
+		for {
+			d.skipWhiteSpace()
+			c := d.pop()
+			l.scratch = jsonValue{}
+			if c != '"' || d.parseString(&l.scratch) != nil {
+				l.fail(d.error(c, "miss key"))
+				return
+			}
+			key := l.scratch.s
+			d.skipWhiteSpace()
+			if c = d.pop(); c != ':' {
+				l.fail(d.error(c, "miss colon"))
+				return
+			}
+			d.next()
+			l.path = append(l.path, pathElem{key: key, index: -1})
+			ok := yield(key)
+			l.path = l.path[:len(l.path)-1]
+			if !ok || l.err != nil {
+				return
+			}
+			d.skipWhiteSpace()
+			switch c = d.pop(); c {
+			case ',':
+				d.next()
+			case '}':
+				d.next()
+				return
+			default:
+				l.fail(d.error(c, "miss comma or curly bracket"))
+				return
+			}
+		}
+	}
+}
+
+// Elements returns an iterator over the indexes of a JSON array being
+// decoded into type t. As with Members, the loop body must consume
+// exactly one value per element.
+func (l *Lexer) Elements(t reflect.Type) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if l.err != nil {
+			return
+		}
+		d := &l.d
+		d.skipWhiteSpace()
+		if d.pop() != '[' {
+			l.expect(t, ValueArray)
+			return
+		}
+		d.next()
+		d.skipWhiteSpace()
+		if d.pop() == ']' {
+			d.next()
+			return
+		}
+		for i := 0; ; i++ {
+			l.path = append(l.path, pathElem{index: i})
+			ok := yield(i)
+			l.path = l.path[:len(l.path)-1]
+			if !ok || l.err != nil {
+				return
+			}
+			d.skipWhiteSpace()
+			switch c := d.pop(); c {
+			case ',':
+				d.next()
+			case ']':
+				d.next()
+				return
+			default:
+				l.fail(d.error(c, "MISS_COMMA_OR_SQUARE_BRACKET"))
+				return
+			}
+		}
+	}
+}
+
+// Field returns the index of the struct field that the member name key
+// decodes into, matching names exactly first and then ignoring case as
+// Unmarshal does, or -1 if there is none.
+func (l *Lexer) Field(key []byte, names []string) int {
+	for i, name := range names {
+		if string(key) == name {
+			return i
+		}
+	}
+	for i, name := range names {
+		if bytes.EqualFold(key, []byte(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Skip consumes the next value.
+func (l *Lexer) Skip() {
+	l.Raw()
+}
+
+// Raw consumes the next value and returns its text.
+func (l *Lexer) Raw() []byte {
+	if l.err != nil {
+		return nil
+	}
+	l.d.skipWhiteSpace()
+	start := l.d.off
+	if err := l.d.skipValue(); err != nil {
+		l.fail(err)
+		return nil
+	}
+	return l.d.data[start:l.d.off]
+}
+
+// Decode consumes the next value and stores it in the value pointed to
+// by v using Unmarshal. Generated code uses it for the types it does
+// not decode itself.
+func (l *Lexer) Decode(v interface{}) {
+	raw := l.Raw()
+	if raw == nil {
+		return
+	}
+	if err := Unmarshal(raw, v); !mergeFieldErrors(&l.fieldErrs, l.path, err) {
+		l.fail(err)
+	}
+}
+
+// Missing records that the required field name is missing from the
+// object being decoded.
+func (l *Lexer) Missing(name string) {
+	l.fieldErrs = append(l.fieldErrs, &FieldError{Path: formatPath(l.path) + "/" + escapePointer(name), Missing: true})
+}
+
+// End checks that only white space follows the decoded value and
+// returns the first error, or the FieldErrors recorded by Missing and
+// Decode.
+func (l *Lexer) End() error {
+	if l.err == nil {
+		l.d.skipWhiteSpace()
+		if l.d.off < len(l.d.data) {
+			l.fail(&SyntaxError{msg: "unexpected end of JSON input", Offset: l.d.off})
+		}
+	}
+	if l.err != nil {
+		return l.err
+	}
+	if len(l.fieldErrs) > 0 {
+		return l.fieldErrs
+	}
+	return nil
+}
+
+// AppendString appends the JSON encoding of s to dst, escaped the same
+// way as Marshal.
+func AppendString(dst []byte, s string) []byte {
+	return appendString(dst, s)
+}
+
+// Empty reports whether the value that p points to is empty as defined
+// by the omitempty struct tag option: false, 0, a nil pointer or
+// interface, or an array, slice, map or string of length zero. Structs
+// are never empty. Generated code uses it for field types that it
+// cannot inspect, such as types from other packages.
+func Empty(p interface{}) bool {
+	return isEmptyValue(reflect.ValueOf(p).Elem())
+}
+
+// AppendFloat appends the JSON encoding of f, a floating-point number
+// of the given bit size, to dst. NaN and infinities are reported as
+// UnsupportedValueError.
+func AppendFloat(dst []byte, f float64, bits int) ([]byte, error) {
+	return appendFloat(dst, f, bits)
+}
@@ -0,0 +1,194 @@
+package json
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// lexerItem 按生成代码的方式手写解码，检验 Lexer 的行为
+type lexerItem struct {
+	ID    int8
+	Name  string
+	Tags  []string
+	Ratio float32
+	Data  []byte
+	Extra map[string]int
+}
+
+func (x *lexerItem) decode(l *Lexer) {
+	seenID := false
+	for key := range l.Members(reflect.TypeFor[lexerItem]()) {
+		switch string(key) {
+		case "id":
+			seenID = true
+			if !l.Null() {
+				x.ID = int8(l.Int(reflect.TypeFor[int8]()))
+			}
+		case "name":
+			if !l.Null() {
+				x.Name = l.String(reflect.TypeFor[string]())
+			}
+		case "tags":
+			if l.Null() {
+				x.Tags = nil
+				break
+			}
+			x.Tags = []string{}
+			for range l.Elements(reflect.TypeFor[[]string]()) {
+				var e string
+				if !l.Null() {
+					e = l.String(reflect.TypeFor[string]())
+				}
+				x.Tags = append(x.Tags, e)
+			}
+		case "ratio":
+			if !l.Null() {
+				x.Ratio = float32(l.Float(reflect.TypeFor[float32]()))
+			}
+		case "data":
+			x.Data = l.Bytes(reflect.TypeFor[[]byte]())
+		case "extra":
+			l.Decode(&x.Extra)
+		default:
+			l.Skip()
+		}
+	}
+	if !seenID {
+		l.Missing("id")
+	}
+}
+
+func lexerDecode(data string) (*lexerItem, error) {
+	l := NewLexer([]byte(data))
+	x := &lexerItem{}
+	if !l.Null() {
+		x.decode(l)
+	}
+	return x, l.End()
+}
+
+func TestLexer(t *testing.T) {
+	x, err := lexerDecode(` { "id" : -3, "name": "a\nb", "tags": ["x", null, "y"], "ratio": 0.5, "skip": {"a": [1, {}]}, "data": "AQI=", "extra": {"k": 1} } `)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, lexerItem{ID: -3, Name: "a\nb", Tags: []string{"x", "", "y"}, Ratio: 0.5, Data: []byte{1, 2}, Extra: map[string]int{"k": 1}}, *x)
+
+	x, err = lexerDecode(`null`)
+	assertTrue(t, err == nil && x.Tags == nil)
+	x, err = lexerDecode(`{"id": 1, "tags": []}`)
+	assertTrue(t, err == nil && x.Tags != nil && len(x.Tags) == 0)
+}
+
+func TestLexerError(t *testing.T) {
+	testLexerError := func(data, msg string) {
+		t.Helper()
+		_, err := lexerDecode(data)
+		if err == nil || err.Error() != msg {
+			t.Errorf("data %s should be error [%s], but error is [%v]", data, msg, err)
+		}
+	}
+	testLexerError(`{"id": 300}`, "json: cannot unmarshal number 300 into Go jsonValue of type int8")
+	testLexerError(`{"id": 1.5}`, "json: cannot unmarshal number 1.5 into Go jsonValue of type int8")
+	testLexerError(`{"id": 1, "name": 2}`, "json: cannot unmarshal number 2 into Go jsonValue of type string")
+	testLexerError(`{"id": 1, "ratio": 1e300}`, "json: cannot unmarshal number 1e300 into Go jsonValue of type float32")
+	testLexerError(`[]`, "json: cannot unmarshal array into Go jsonValue of type json.lexerItem")
+	testLexerError(`{"id": 1, "tags": "x"}`, "json: cannot unmarshal string into Go jsonValue of type []string")
+	testLexerError(`{"id": 1 "name": ""}`, "invalid character \" miss comma or curly bracket")
+	testLexerError(`{"id": 1, "tags": [1 2]}`, "json: cannot unmarshal number 1 into Go jsonValue of type string")
+	testLexerError(`{"id": 1, "tags": ["a" "b"]}`, "invalid character \" MISS_COMMA_OR_SQUARE_BRACKET")
+	testLexerError(`{"id": 1} x`, "unexpected end of JSON input")
+	testLexerError(`{"name": ""}`, `json: missing required field "/id"`)
+
+	_, err := lexerDecode(`{"id": 1, "extra": {"k": "v"}}`)
+	var te *UnmarshalTypeError
+	assertTrue(t, errors.As(err, &te))
+
+	// 嵌套值中的字段错误带上当前路径
+	l := NewLexer([]byte(`[{"a": 1}, {"id": 2}]`))
+	for range l.Elements(reflect.TypeFor[[]lexerItem]()) {
+		var x struct {
+			ID int `json:"id,required"`
+		}
+		l.Decode(&x)
+	}
+	assertEqual(t, `json: missing required field "/0/id"`, l.End().Error())
+}
+
+// fastItem 的 MarshalJSON 和 UnmarshalJSON 总是失败，只有经过 Appender 和 LexerDecoder 才能成功
+type fastItem struct{ N int64 }
+
+func (x fastItem) MarshalJSON() ([]byte, error) { return nil, errors.New("MarshalJSON called") }
+
+func (x fastItem) AppendJSON(b []byte) ([]byte, error) { return strconv.AppendInt(b, x.N, 10), nil }
+
+func (x *fastItem) UnmarshalJSON([]byte) error { return errors.New("UnmarshalJSON called") }
+
+func (x *fastItem) DecodeJSON(l *Lexer) {
+	if !l.Null() {
+		x.N = l.Int(reflect.TypeFor[int64]())
+	}
+}
+
+func TestAppenderLexerDecoder(t *testing.T) {
+	testMarshal(t, `1`, fastItem{1})
+	testMarshal(t, `{"A":[1,2],"P":3}`, struct {
+		A []fastItem
+		P *fastItem
+	}{[]fastItem{{1}, {2}}, &fastItem{3}})
+
+	var x fastItem
+	assertTrue(t, Unmarshal([]byte(` 5 `), &x) == nil)
+	assertEqual(t, int64(5), x.N)
+	var s struct{ A []fastItem }
+	assertTrue(t, Unmarshal([]byte(`{"A": [1, null, 3]}`), &s) == nil)
+	assertEqual(t, []fastItem{{1}, {0}, {3}}, s.A)
+
+	err := Unmarshal([]byte(`{"A": [1, "x"]}`), &s)
+	var te *UnmarshalTypeError
+	assertTrue(t, errors.As(err, &te))
+	err = Unmarshal([]byte(`5 6`), &x)
+	var se *SyntaxError
+	assertTrue(t, errors.As(err, &se))
+
+	// 没有原文的值仍然调用 UnmarshalJSON
+	v, err := ParseJSON5([]byte(`[+1]`))
+	assertTrue(t, err == nil)
+	_, err = DecodeValue[[]fastItem](v)
+	assertEqual(t, "UnmarshalJSON called", err.Error())
+}
+
+// badAppender 追加的内容不是紧凑的或者不是合法的 JSON
+type badAppender string
+
+func (x badAppender) MarshalJSON() ([]byte, error) { return nil, errors.New("MarshalJSON called") }
+
+func (x badAppender) AppendJSON(b []byte) ([]byte, error) { return append(b, x...), nil }
+
+func TestAppenderChecked(t *testing.T) {
+	testMarshal(t, `{"a":[1,"x y"],"b":{"c":null}}`, map[string]badAppender{
+		"a": " [ 1 , \"x y\" ] ",
+		"b": "{\n\t\"c\": null\n}",
+	})
+	for _, s := range []badAppender{"", "[1", "1 2", "nul", `"a`} {
+		_, err := Marshal([]badAppender{"1", s})
+		var me *MarshalerError
+		assertTrue(t, errors.As(err, &me))
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	var p *int
+	var e interface{}
+	for _, v := range []interface{}{new(bool), new(int8), new(float64), new(string), new([]int), new(map[string]int), new([0]int), &p, &e} {
+		assertTrue(t, Empty(v))
+	}
+	n, s := 1, struct{}{}
+	q := &n
+	e = 0
+	for _, v := range []interface{}{&n, &[]int{0}, &[1]int{}, &e, &s, &q} {
+		assertFalse(t, Empty(v))
+	}
+}
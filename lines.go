@@ -0,0 +1,125 @@
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// A LineError records a failure to parse one line of newline-delimited JSON.
+type LineError struct {
+	Line int // 1-based line number
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+}
+
+func (e *LineError) Unwrap() error { return e.Err }
+
+// A LinesReader reads newline-delimited JSON (NDJSON / JSON Lines),
+// one Value per line.
+type LinesReader struct {
+	r               *bufio.Reader
+	skipBlank       bool
+	continueOnError bool
+	line            int
+	value           *Value
+	err             error
+	errs            []error
+}
+
+// NewLinesReader returns a LinesReader that reads from r.
+func NewLinesReader(r io.Reader) *LinesReader {
+	return &LinesReader{r: bufio.NewReader(r)}
+}
+
+// SkipBlankLines causes blank lines to be ignored instead of
+// reported as errors.
+func (lr *LinesReader) SkipBlankLines() { lr.skipBlank = true }
+
+// ContinueOnError causes malformed lines to be skipped; their errors
+// are collected and available from Errors.
+func (lr *LinesReader) ContinueOnError() { lr.continueOnError = true }
+
+// Next advances to the next record. It returns false at the end of
+// the input or on the first error that is not skipped.
+func (lr *LinesReader) Next() bool {
+	lr.value = nil
+	for lr.err == nil {
+		line, err := lr.r.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			if err != io.EOF {
+				lr.err = err
+			}
+			return false
+		}
+		lr.line++
+		line = bytes.TrimRight(line, "\r\n")
+		if lr.skipBlank && len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		v, perr := Parse(line)
+		if perr == nil {
+			lr.value = v
+			return true
+		}
+		lerr := &LineError{Line: lr.line, Err: perr}
+		if !lr.continueOnError {
+			lr.err = lerr
+			return false
+		}
+		lr.errs = append(lr.errs, lerr)
+	}
+	return false
+}
+
+// Value returns the record read by the last call to Next.
+func (lr *LinesReader) Value() *Value { return lr.value }
+
+// Line returns the 1-based line number of the current record.
+func (lr *LinesReader) Line() int { return lr.line }
+
+// Decode stores the current record in the value pointed to by v.
+func (lr *LinesReader) Decode(v interface{}) error {
+	if lr.value == nil {
+		return &LineError{Line: lr.line, Err: io.ErrUnexpectedEOF}
+	}
+	if err := unmarshalValue(lr.value, v); err != nil {
+		return &LineError{Line: lr.line, Err: err}
+	}
+	return nil
+}
+
+// Err returns the error that stopped the reader, if any.
+func (lr *LinesReader) Err() error { return lr.err }
+
+// Errors returns the errors of the lines skipped under ContinueOnError.
+func (lr *LinesReader) Errors() []error { return lr.errs }
+
+// A LinesWriter writes newline-delimited JSON, one compact record per line.
+type LinesWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewLinesWriter returns a LinesWriter that writes to w.
+func NewLinesWriter(w io.Writer) *LinesWriter {
+	return &LinesWriter{w: w}
+}
+
+// Encode writes the JSON encoding of v followed by a newline.
+// The encoding never contains a raw newline, so every record
+// occupies exactly one line.
+func (lw *LinesWriter) Encode(v interface{}) error {
+	e := &encodeState{buf: lw.buf[:0]}
+	if err := e.reflectValue(reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	lw.buf = append(e.buf, '\n')
+	_, err := lw.w.Write(lw.buf)
+	return err
+}
@@ -0,0 +1,84 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type logRecord struct {
+	Level string `json:"level"`
+	Msg   string `json:"msg"`
+}
+
+func TestLinesReader(t *testing.T) {
+	lr := NewLinesReader(strings.NewReader("{\"level\":\"info\",\"msg\":\"a\"}\r\n\n{\"level\":\"warn\",\"msg\":\"b\"}\n[1,2]"))
+	lr.SkipBlankLines()
+	var records []logRecord
+	var lines []int
+	for lr.Next() {
+		lines = append(lines, lr.Line())
+		if lr.Value().getValueType() != ValueObject {
+			continue
+		}
+		var r logRecord
+		if err := lr.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	assertTrue(t, lr.Err() == nil)
+	assertEqual(t, []int{1, 3, 4}, lines)
+	assertEqual(t, []logRecord{{"info", "a"}, {"warn", "b"}}, records)
+}
+
+func TestLinesReaderError(t *testing.T) {
+	input := "1\n\n{bad}\n2\n"
+	lr := NewLinesReader(strings.NewReader(input))
+	count := 0
+	for lr.Next() {
+		count++
+	}
+	assertEqual(t, 1, count)
+	var lerr *LineError
+	assertTrue(t, errors.As(lr.Err(), &lerr) && lerr.Line == 2)
+
+	lr = NewLinesReader(strings.NewReader(input))
+	lr.ContinueOnError()
+	count = 0
+	for lr.Next() {
+		count++
+	}
+	assertEqual(t, 2, count)
+	assertTrue(t, lr.Err() == nil)
+	assertEqual(t, 2, len(lr.Errors()))
+	assertTrue(t, strings.HasPrefix(lr.Errors()[1].Error(), "line 3: "))
+}
+
+func TestLinesWriter(t *testing.T) {
+	var buf bytes.Buffer
+	lw := NewLinesWriter(&buf)
+	if err := lw.Encode(logRecord{Level: "info", Msg: "multi\nline"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := lw.Encode(mustParse(t, "{\n  \"a\" : [ 1 ,\n 2 ]\n}")); err != nil {
+		t.Fatal(err)
+	}
+	var raw struct{ P RawMessage }
+	if err := Unmarshal([]byte("{\"P\": {\n \"x\": 1\n}}"), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if err := lw.Encode(raw); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "{\"level\":\"info\",\"msg\":\"multi\\nline\"}\n{\"a\":[1,2]}\n{\"P\":{\"x\":1}}\n", buf.String())
+
+	// 写回后逐行读取应得到同样的记录
+	lr := NewLinesReader(&buf)
+	count := 0
+	for lr.Next() {
+		count++
+	}
+	assertEqual(t, 3, count)
+}
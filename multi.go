@@ -0,0 +1,60 @@
+package json
+
+import "iter"
+
+// A Document is one top-level value of a buffer holding several
+// concatenated JSON values, with its byte range data[Start:End].
+type Document struct {
+	Value *Value
+	Start int
+	End   int
+}
+
+// ParseAll parses every top-level value in data. Values may be
+// separated by whitespace or directly concatenated, as in
+// `{"a":1}{"b":2}`, except that two numbers, literals or strings in a
+// row must be separated by whitespace. It stops at the first syntax
+// error.
+func ParseAll(data []byte) ([]Document, error) {
+	var docs []Document
+	for doc, err := range Documents(data) {
+		if err != nil {
+			return docs, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// Documents returns an iterator over the top-level values in data,
+// parsing each one only when the loop asks for it. A syntax error is
+// yielded once and ends the iteration.
+func Documents(data []byte) iter.Seq2[Document, error] {
+	return func(yield func(Document, error) bool) {
+		d := new(jsonParse)
+		d.init(data)
+		scalar := false
+		for {
+			end := d.off
+			d.skipWhiteSpace()
+			if d.off >= len(d.data) {
+				return
+			}
+			start := d.off
+			// 两个标量之间必须有空白，否则 1-2、truefalse 无法区分
+			if c := d.pop(); scalar && start == end && c != '[' && c != '{' {
+				yield(Document{Start: start, End: start}, d.error(c, "after top-level value"))
+				return
+			}
+			v, err := d.parserValue()
+			if err != nil {
+				yield(Document{Start: start, End: d.off}, err)
+				return
+			}
+			if !yield(Document{Value: v, Start: start, End: d.off}, nil) {
+				return
+			}
+			scalar = v.valueType != ValueArray && v.valueType != ValueObject
+		}
+	}
+}
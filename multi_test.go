@@ -0,0 +1,53 @@
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAll(t *testing.T) {
+	data := []byte(` {"a":1}{"b":2}
+[3] "x"	null 4.5 `)
+	docs, err := ParseAll(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, doc := range docs {
+		texts = append(texts, string(data[doc.Start:doc.End]))
+	}
+	assertEqual(t, []string{`{"a":1}`, `{"b":2}`, `[3]`, `"x"`, `null`, `4.5`}, texts)
+	assertTrue(t, docs[1].Value.getValueType() == ValueObject)
+	assertTrue(t, docs[4].Value.getValueType() == ValueNull)
+
+	docs, err = ParseAll([]byte("  \n"))
+	assertTrue(t, err == nil && len(docs) == 0)
+}
+
+func TestParseAllError(t *testing.T) {
+	docs, err := ParseAll([]byte(`{"a":1} [1 2]`))
+	assertEqual(t, 1, len(docs))
+	assertTrue(t, err != nil && strings.Contains(err.Error(), "MISS_COMMA_OR_SQUARE_BRACKET"))
+
+	// 相邻的标量之间必须有空白
+	for _, s := range []string{"1-2", "truefalse", `"a""b"`, `null"x"`, `[1]2 3"c"`} {
+		_, err := ParseAll([]byte(s))
+		assertTrue(t, err != nil && strings.Contains(err.Error(), "after top-level value"))
+	}
+	docs, err = ParseAll([]byte(`1[2]{"a":3}4 true`))
+	assertTrue(t, err == nil && len(docs) == 5)
+}
+
+func TestDocuments(t *testing.T) {
+	count := 0
+	for doc, err := range Documents([]byte(`1 2 3 4`)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+		if n, _ := doc.Value.getNumber(); n == 2 {
+			break
+		}
+	}
+	assertEqual(t, 2, count)
+}
@@ -0,0 +1,105 @@
+package json
+
+import (
+	"errors"
+	"iter"
+	"reflect"
+)
+
+// An OrderedMap is a JSON object that remembers the order of its
+// members. A Decoder stores objects as *OrderedMap when decoding into
+// an interface value under UseOrderedMap, and Marshal writes the
+// members back in the same order. A key set more than once keeps its
+// first position and its last value.
+//
+// The zero value is an empty map ready to use.
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// Len returns the number of members in m.
+func (m *OrderedMap) Len() int { return len(m.keys) }
+
+// Keys returns the member names of m in order.
+func (m *OrderedMap) Keys() []string { return m.keys }
+
+// Get returns the value of the member named key.
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// Set sets the member named key to v, appending it if it is new.
+func (m *OrderedMap) Set(key string, v interface{}) {
+	if m.values == nil {
+		m.values = make(map[string]interface{})
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = v
+}
+
+// All returns an iterator over the members of m in order.
+func (m *OrderedMap) All() iter.Seq2[string, interface{}] {
+	return func(yield func(string, interface{}) bool) {
+		for _, k := range m.keys {
+			if !yield(k, m.values[k]) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON 按成员顺序生成对象
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	e := &encodeState{}
+	if err := m.encode(e); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+// encode 把 m 直接写入 e。marshaler 对 *OrderedMap 调用它，
+// 嵌套的 OrderedMap 不会逐层经过 MarshalJSON 再重新校验和压缩
+func (m *OrderedMap) encode(e *encodeState) error {
+	e.buf = append(e.buf, '{')
+	for i, k := range m.keys {
+		if i > 0 {
+			e.buf = append(e.buf, ',')
+		}
+		e.buf = appendString(e.buf, k)
+		e.buf = append(e.buf, ':')
+		if err := e.reflectValue(reflect.ValueOf(m.values[k])); err != nil {
+			return err
+		}
+	}
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+// UnmarshalJSON 解析一个对象，嵌套的对象同样保持顺序；null 不做任何修改
+func (m *OrderedMap) UnmarshalJSON(data []byte) error {
+	if m == nil {
+		return errors.New("json.OrderedMap: UnmarshalJSON on nil pointer")
+	}
+	jv, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	d := &decodeState{orderedMap: true}
+	switch jv.getValueType() {
+	case ValueNull:
+		return nil
+	case ValueObject:
+	default:
+		return d.typeError(jv, reflect.ValueOf(m))
+	}
+	x, err := d.valueInterface(jv)
+	if err != nil {
+		return err
+	}
+	*m = *x.(*OrderedMap)
+	return nil
+}
@@ -0,0 +1,70 @@
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap
+	m.Set("b", 1)
+	m.Set("a", "x")
+	m.Set("b", 2)
+	assertEqual(t, 2, m.Len())
+	assertEqual(t, []string{"b", "a"}, m.Keys())
+	v, ok := m.Get("b")
+	assertTrue(t, ok && v == 2)
+	_, ok = m.Get("c")
+	assertFalse(t, ok)
+
+	var keys []string
+	for k := range m.All() {
+		keys = append(keys, k)
+		break
+	}
+	assertEqual(t, []string{"b"}, keys)
+
+	b, err := Marshal(&m)
+	assertTrue(t, err == nil)
+	assertEqual(t, `{"b":2,"a":"x"}`, string(b))
+	b, err = Marshal(&OrderedMap{})
+	assertTrue(t, err == nil)
+	assertEqual(t, `{}`, string(b))
+}
+
+func TestOrderedMapUnmarshal(t *testing.T) {
+	var v struct {
+		M *OrderedMap
+		N OrderedMap
+	}
+	if err := Unmarshal([]byte(`{"M":{"z":[{"y":1,"x":2}],"a":"s"},"N":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, []string{"z", "a"}, v.M.Keys())
+	z, _ := v.M.Get("z")
+	assertEqual(t, []string{"y", "x"}, z.([]interface{})[0].(*OrderedMap).Keys())
+	assertEqual(t, 0, v.N.Len())
+
+	testUnmarshalError(t, `{"M":[1]}`, &v, "cannot unmarshal array into Go jsonValue of type *json.OrderedMap")
+}
+
+func TestOrderedMapNilReceiver(t *testing.T) {
+	var m *OrderedMap
+	err := m.UnmarshalJSON([]byte(`{"a":1}`))
+	assertTrue(t, err != nil && strings.Contains(err.Error(), "nil pointer"))
+}
+
+func TestOrderedMapNested(t *testing.T) {
+	// 嵌套的 OrderedMap 直接写入，不会在每一层重新解析
+	const depth = 2000
+	m := &OrderedMap{}
+	m.Set("v", 1)
+	for i := 0; i < depth; i++ {
+		p := &OrderedMap{}
+		p.Set("m", m)
+		m = p
+	}
+	b, err := Marshal(m)
+	assertTrue(t, err == nil)
+	assertEqual(t, strings.Repeat(`{"m":`, depth)+`{"v":1}`+strings.Repeat("}", depth), string(b))
+}
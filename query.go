@@ -0,0 +1,739 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"iter"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// A Query is a compiled filter in a subset of the jq language.
+//
+// The supported forms are:
+//
+//	.                     identity
+//	.foo  ."foo"  .[e]    object member or array element, null when missing
+//	.[]                   every element or member value
+//	a | b                 feed every output of a into b
+//	a, b                  outputs of a followed by outputs of b
+//	[e]  {k: e, k, (e): e} array and object construction
+//	==  !=  <  <=  >  >=  comparison, using jq's ordering of values
+//	and  or               boolean operators
+//	"s"  1.5  true  false  null
+//	select(f)  map(f)  keys  length  not
+//
+// A Query is safe for concurrent use.
+type Query struct {
+	src  string
+	root queryNode
+}
+
+// ParseQuery compiles src. A malformed filter is reported as a
+// *SyntaxError whose Offset is a byte offset into src.
+func ParseQuery(src string) (*Query, error) {
+	d := &queryParse{}
+	d.init([]byte(src))
+	root, err := d.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	d.skipWhiteSpace()
+	if c := d.pop(); c != 0 {
+		return nil, d.error(c, "unexpected token")
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+func (q *Query) String() string { return q.src }
+
+var errStopQuery = errors.New("json: query stopped")
+
+// Run returns an iterator over the outputs of q applied to v. An
+// evaluation error is yielded once and ends the iteration.
+func (q *Query) Run(v *Value) iter.Seq2[*Value, error] {
+	return func(yield func(*Value, error) bool) {
+		err := q.root.eval(v, func(r *jsonValue) error {
+			if !yield(r, nil) {
+				return errStopQuery
+			}
+			return nil
+		})
+		if err != nil && err != errStopQuery {
+			yield(nil, err)
+		}
+	}
+}
+
+// queryNode 是编译后的过滤器，对输入产生零个或多个输出，每个输出交给 out
+type queryNode interface {
+	eval(in *jsonValue, out func(*jsonValue) error) error
+}
+
+type queryIdentity struct{}
+
+type queryLiteral struct{ v *jsonValue }
+
+type queryPipe struct{ left, right queryNode }
+
+type queryComma struct{ left, right queryNode }
+
+type queryIndex struct{ target, key queryNode }
+
+type queryIterate struct{ target queryNode }
+
+type queryCompare struct {
+	op          string
+	left, right queryNode
+}
+
+type queryLogic struct {
+	and         bool
+	left, right queryNode
+}
+
+type queryArray struct{ body queryNode }
+
+type queryObject struct{ keys, values []queryNode }
+
+type queryCall struct {
+	name string
+	arg  queryNode
+}
+
+func (queryIdentity) eval(in *jsonValue, out func(*jsonValue) error) error {
+	return out(in)
+}
+
+func (n *queryLiteral) eval(in *jsonValue, out func(*jsonValue) error) error {
+	return out(n.v)
+}
+
+func (n *queryPipe) eval(in *jsonValue, out func(*jsonValue) error) error {
+	return n.left.eval(in, func(v *jsonValue) error {
+		return n.right.eval(v, out)
+	})
+}
+
+func (n *queryComma) eval(in *jsonValue, out func(*jsonValue) error) error {
+	if err := n.left.eval(in, out); err != nil {
+		return err
+	}
+	return n.right.eval(in, out)
+}
+
+func (n *queryIndex) eval(in *jsonValue, out func(*jsonValue) error) error {
+	return n.target.eval(in, func(t *jsonValue) error {
+		// 和 jq 一样，下标表达式的输入是原来的 .
+		return n.key.eval(in, func(k *jsonValue) error {
+			v, err := queryIndexValue(t, k)
+			if err != nil {
+				return err
+			}
+			return out(v)
+		})
+	})
+}
+
+func (n *queryIterate) eval(in *jsonValue, out func(*jsonValue) error) error {
+	return n.target.eval(in, func(t *jsonValue) error {
+		if err := t.load(); err != nil {
+			return err
+		}
+		var values []*jsonValue
+		switch t.getValueType() {
+		case ValueArray:
+			values = t.array.values
+		case ValueObject:
+			values = t.object.values
+		default:
+			return &jsonValueError{"cannot iterate over " + queryTypeName(t)}
+		}
+		for _, v := range values {
+			if err := out(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (n *queryCompare) eval(in *jsonValue, out func(*jsonValue) error) error {
+	return n.left.eval(in, func(l *jsonValue) error {
+		return n.right.eval(in, func(r *jsonValue) error {
+			c := compareValues(l, r)
+			var b bool
+			switch n.op {
+			case "==":
+				b = c == 0
+			case "!=":
+				b = c != 0
+			case "<":
+				b = c < 0
+			case "<=":
+				b = c <= 0
+			case ">":
+				b = c > 0
+			case ">=":
+				b = c >= 0
+			}
+			return out(newBoolValue(b))
+		})
+	})
+}
+
+func (n *queryLogic) eval(in *jsonValue, out func(*jsonValue) error) error {
+	return n.left.eval(in, func(l *jsonValue) error {
+		// 短路求值：and 左边为假、or 左边为真时不再计算右边
+		if isTruthy(l) != n.and {
+			return out(newBoolValue(!n.and))
+		}
+		return n.right.eval(in, func(r *jsonValue) error {
+			return out(newBoolValue(isTruthy(r)))
+		})
+	})
+}
+
+func (n *queryArray) eval(in *jsonValue, out func(*jsonValue) error) error {
+	var values []*jsonValue
+	if n.body != nil {
+		err := n.body.eval(in, func(v *jsonValue) error {
+			values = append(values, v)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return out(newArrayValue(values))
+}
+
+func (n *queryObject) eval(in *jsonValue, out func(*jsonValue) error) error {
+	return n.build(in, 0, nil, nil, out)
+}
+
+// build 逐个成员求值，key 或 value 产生多个输出时生成它们的笛卡尔积
+func (n *queryObject) build(in *jsonValue, i int, keys, values []*jsonValue, out func(*jsonValue) error) error {
+	if i == len(n.keys) {
+		return out(newObjectValue(keys, values))
+	}
+	return n.keys[i].eval(in, func(k *jsonValue) error {
+		if k.getValueType() != ValueString {
+			return &jsonValueError{"object key must be a string, not " + queryTypeName(k)}
+		}
+		return n.values[i].eval(in, func(v *jsonValue) error {
+			return n.build(in, i+1, append(keys[:i:i], k), append(values[:i:i], v), out)
+		})
+	})
+}
+
+func (n *queryCall) eval(in *jsonValue, out func(*jsonValue) error) error {
+	switch n.name {
+	case "select":
+		return n.arg.eval(in, func(v *jsonValue) error {
+			if isTruthy(v) {
+				return out(in)
+			}
+			return nil
+		})
+	case "map":
+		return (&queryArray{&queryPipe{&queryIterate{queryIdentity{}}, n.arg}}).eval(in, out)
+	case "not":
+		return out(newBoolValue(!isTruthy(in)))
+	case "length":
+		if err := in.load(); err != nil {
+			return err
+		}
+		switch in.getValueType() {
+		case ValueNull:
+			return out(newNumberValue(0))
+		case ValueNumber:
+			return out(newNumberValue(math.Abs(in.n)))
+		case ValueString:
+			return out(newNumberValue(float64(utf8.RuneCount(in.s))))
+		case ValueArray:
+			return out(newNumberValue(float64(in.getArrayLen())))
+		case ValueObject:
+			return out(newNumberValue(float64(in.getObjectSize())))
+		}
+		return &jsonValueError{queryTypeName(in) + " has no length"}
+	case "keys":
+		if err := in.load(); err != nil {
+			return err
+		}
+		var keys []*jsonValue
+		switch in.getValueType() {
+		case ValueArray:
+			for i := 0; i < in.getArrayLen(); i++ {
+				keys = append(keys, newNumberValue(float64(i)))
+			}
+		case ValueObject:
+			keys = append(keys, in.object.keys...)
+			sort.SliceStable(keys, func(i, j int) bool { return bytes.Compare(keys[i].s, keys[j].s) < 0 })
+		default:
+			return &jsonValueError{queryTypeName(in) + " has no keys"}
+		}
+		return out(newArrayValue(keys))
+	}
+	return &jsonValueError{"unknown function " + n.name}
+}
+
+// queryIndexValue 实现 .[k]：对象按字符串取成员，数组按数字取元素，缺失时为 null
+func queryIndexValue(t, k *jsonValue) (*jsonValue, error) {
+	if err := t.load(); err != nil {
+		return nil, err
+	}
+	switch {
+	case t.getValueType() == ValueNull && (k.getValueType() == ValueString || k.getValueType() == ValueNumber):
+		return &jsonValue{}, nil
+	case t.getValueType() == ValueObject && k.getValueType() == ValueString:
+		if v := lookupMember(t, k.s); v != nil {
+			return v, nil
+		}
+		return &jsonValue{}, nil
+	case t.getValueType() == ValueArray && k.getValueType() == ValueNumber:
+		i := int(math.Floor(k.n))
+		if i < 0 {
+			i += t.getArrayLen()
+		}
+		if i < 0 || i >= t.getArrayLen() {
+			return &jsonValue{}, nil
+		}
+		return t.array.values[i], nil
+	}
+	return nil, &jsonValueError{"cannot index " + queryTypeName(t) + " with " + queryTypeName(k)}
+}
+
+// lookupMember 返回对象中第一个名为 key 的成员
+func lookupMember(v *jsonValue, key []byte) *jsonValue {
+	for i := 0; i < v.getObjectSize(); i++ {
+		if bytes.Equal(v.object.keys[i].s, key) {
+			return v.object.values[i]
+		}
+	}
+	return nil
+}
+
+// compareValues 按 jq 的顺序比较：null < false < true < 数字 < 字符串 < 数组 < 对象
+func compareValues(a, b *jsonValue) int {
+	a.load()
+	b.load()
+	ta, tb := a.getValueType(), b.getValueType()
+	if ta != tb {
+		return int(ta) - int(tb)
+	}
+	switch ta {
+	case ValueNumber:
+		switch {
+		case a.n < b.n:
+			return -1
+		case a.n > b.n:
+			return 1
+		}
+	case ValueString:
+		return bytes.Compare(a.s, b.s)
+	case ValueArray:
+		for i := 0; i < a.getArrayLen() && i < b.getArrayLen(); i++ {
+			if c := compareValues(a.array.values[i], b.array.values[i]); c != 0 {
+				return c
+			}
+		}
+		return a.getArrayLen() - b.getArrayLen()
+	case ValueObject:
+		// 先比较排序后的 key 集合，再按 key 的顺序比较成员
+		ka, kb := sortedKeys(a), sortedKeys(b)
+		for i := 0; i < len(ka) && i < len(kb); i++ {
+			if c := bytes.Compare(ka[i], kb[i]); c != 0 {
+				return c
+			}
+		}
+		if len(ka) != len(kb) {
+			return len(ka) - len(kb)
+		}
+		for _, k := range ka {
+			if c := compareValues(lookupMember(a, k), lookupMember(b, k)); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func sortedKeys(v *jsonValue) [][]byte {
+	keys := make([][]byte, v.getObjectSize())
+	for i := range keys {
+		keys[i] = v.object.keys[i].s
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	return keys
+}
+
+func isTruthy(v *jsonValue) bool {
+	return v.getValueType() != ValueNull && v.getValueType() != ValueFalse
+}
+
+func queryTypeName(v *jsonValue) string {
+	switch v.getValueType() {
+	case ValueNull:
+		return "null"
+	case ValueFalse, ValueTrue:
+		return "boolean"
+	case ValueNumber:
+		return "number"
+	case ValueString:
+		return "string"
+	case ValueArray:
+		return "array"
+	case ValueObject:
+		return "object"
+	}
+	return "invalid"
+}
+
+func newBoolValue(b bool) *jsonValue {
+	if b {
+		return &jsonValue{valueType: ValueTrue}
+	}
+	return &jsonValue{valueType: ValueFalse}
+}
+
+func newNumberValue(n float64) *jsonValue {
+	return &jsonValue{valueType: ValueNumber, n: n}
+}
+
+func newStringValue(s string) *jsonValue {
+	return &jsonValue{valueType: ValueString, s: []byte(s)}
+}
+
+func newArrayValue(values []*jsonValue) *jsonValue {
+	return &jsonValue{valueType: ValueArray, array: array{len: len(values), values: values}}
+}
+
+func newObjectValue(keys, values []*jsonValue) *jsonValue {
+	return &jsonValue{valueType: ValueObject, object: object{size: len(keys), keys: keys, values: values}}
+}
+
+// queryParse 复用 jsonParse 扫描过滤器文本，字符串和数字字面量按 JSON 语法解析
+//
+//	pipe    = comma { "|" comma }
+//	comma   = or { "," or }
+//	or      = and { "or" and }
+//	and     = compare { "and" compare }
+//	compare = postfix [ op postfix ]
+//	postfix = term { "." key | "[" "]" | "[" pipe "]" }
+type queryParse struct {
+	jsonParse
+}
+
+func (d *queryParse) parsePipe() (queryNode, error) {
+	left, err := d.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	for d.skipWhiteSpace(); d.pop() == '|'; d.skipWhiteSpace() {
+		d.next()
+		right, err := d.parseComma()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryPipe{left, right}
+	}
+	return left, nil
+}
+
+func (d *queryParse) parseComma() (queryNode, error) {
+	left, err := d.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for d.skipWhiteSpace(); d.pop() == ','; d.skipWhiteSpace() {
+		d.next()
+		right, err := d.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryComma{left, right}
+	}
+	return left, nil
+}
+
+func (d *queryParse) parseOr() (queryNode, error) {
+	left, err := d.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for d.skipWhiteSpace(); d.keyword("or"); d.skipWhiteSpace() {
+		right, err := d.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryLogic{false, left, right}
+	}
+	return left, nil
+}
+
+func (d *queryParse) parseAnd() (queryNode, error) {
+	left, err := d.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+	for d.skipWhiteSpace(); d.keyword("and"); d.skipWhiteSpace() {
+		right, err := d.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryLogic{true, left, right}
+	}
+	return left, nil
+}
+
+func (d *queryParse) parseCompare() (queryNode, error) {
+	left, err := d.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	d.skipWhiteSpace()
+	op := d.compareOp()
+	if op == "" {
+		return left, nil
+	}
+	right, err := d.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	return &queryCompare{op, left, right}, nil
+}
+
+// compareOp 读取一个比较运算符，没有时返回 ""
+func (d *queryParse) compareOp() string {
+	rest := d.data[d.off:]
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if bytes.HasPrefix(rest, []byte(op)) {
+			d.off += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+// keyword 读取一个完整的标识符 word，后面紧跟标识符字符时不算匹配
+func (d *queryParse) keyword(word string) bool {
+	rest := d.data[d.off:]
+	if !bytes.HasPrefix(rest, []byte(word)) || len(rest) > len(word) && isQueryIdent(rest[len(word)]) {
+		return false
+	}
+	d.off += len(word)
+	return true
+}
+
+func (d *queryParse) parsePostfix() (queryNode, error) {
+	n, err := d.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch d.pop() {
+		case '.':
+			c := d.peekByte(1)
+			if c == '[' {
+				d.next()
+				continue
+			}
+			if c != '"' && !isQueryIdentStart(c) {
+				return n, nil
+			}
+			d.next()
+			key, err := d.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			n = &queryIndex{n, key}
+		case '[':
+			d.next()
+			d.skipWhiteSpace()
+			if d.pop() == ']' {
+				d.next()
+				n = &queryIterate{n}
+				continue
+			}
+			key, err := d.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := d.expect(']', "miss square bracket"); err != nil {
+				return nil, err
+			}
+			n = &queryIndex{n, key}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (d *queryParse) parseTerm() (queryNode, error) {
+	d.skipWhiteSpace()
+	switch c := d.pop(); {
+	case c == '.':
+		d.next()
+		if c = d.pop(); c == '"' || isQueryIdentStart(c) {
+			key, err := d.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			return &queryIndex{queryIdentity{}, key}, nil
+		}
+		return queryIdentity{}, nil
+	case c == '"':
+		return d.parseKey()
+	case c == '-' || isDigit(c):
+		v := &jsonValue{}
+		if err := d.parseNumber(v); err != nil {
+			return nil, err
+		}
+		return &queryLiteral{v}, nil
+	case c == '(':
+		d.next()
+		n, err := d.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return n, d.expect(')', "miss parenthesis")
+	case c == '[':
+		d.next()
+		d.skipWhiteSpace()
+		if d.pop() == ']' {
+			d.next()
+			return &queryArray{}, nil
+		}
+		n, err := d.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return &queryArray{n}, d.expect(']', "miss square bracket")
+	case c == '{':
+		return d.parseObjectCons()
+	case isQueryIdentStart(c):
+		return d.parseCall()
+	default:
+		return nil, d.error(c, "miss term")
+	}
+}
+
+// parseKey 解析 . 后面的 foo 或 "foo"，得到一个字符串字面量
+func (d *queryParse) parseKey() (queryNode, error) {
+	if d.pop() == '"' {
+		v := &jsonValue{}
+		if err := d.parseString(v); err != nil {
+			return nil, err
+		}
+		return &queryLiteral{v}, nil
+	}
+	return &queryLiteral{newStringValue(d.ident())}, nil
+}
+
+func (d *queryParse) ident() string {
+	start := d.off
+	for d.off < len(d.data) && isQueryIdent(d.data[d.off]) {
+		d.off++
+	}
+	return string(d.data[start:d.off])
+}
+
+func (d *queryParse) parseCall() (queryNode, error) {
+	start := d.off
+	name := d.ident()
+	switch name {
+	case "null":
+		return &queryLiteral{&jsonValue{}}, nil
+	case "true":
+		return &queryLiteral{newBoolValue(true)}, nil
+	case "false":
+		return &queryLiteral{newBoolValue(false)}, nil
+	case "keys", "length", "not":
+		return &queryCall{name: name}, nil
+	case "select", "map":
+		d.skipWhiteSpace()
+		if err := d.expect('(', "miss parenthesis"); err != nil {
+			return nil, err
+		}
+		arg, err := d.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return &queryCall{name, arg}, d.expect(')', "miss parenthesis")
+	}
+	return nil, &SyntaxError{msg: "unknown function " + name, Offset: start}
+}
+
+// parseObjectCons 解析 {k: e, "k": e, (e): e, k}，k 单独出现时是 k: .k 的简写
+func (d *queryParse) parseObjectCons() (queryNode, error) {
+	n := &queryObject{}
+	d.next()
+	d.skipWhiteSpace()
+	if d.pop() == '}' {
+		d.next()
+		return n, nil
+	}
+	for {
+		d.skipWhiteSpace()
+		var key queryNode
+		var err error
+		switch c := d.pop(); {
+		case c == '"' || isQueryIdentStart(c):
+			key, err = d.parseKey()
+		case c == '(':
+			d.next()
+			if key, err = d.parsePipe(); err == nil {
+				err = d.expect(')', "miss parenthesis")
+			}
+		default:
+			err = d.error(c, "miss key")
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.skipWhiteSpace()
+		var value queryNode
+		if c := d.pop(); c == ':' {
+			d.next()
+			if value, err = d.parseOr(); err != nil {
+				return nil, err
+			}
+		} else if _, ok := key.(*queryLiteral); ok {
+			value = &queryIndex{queryIdentity{}, key}
+		} else {
+			return nil, d.error(c, "miss colon")
+		}
+		n.keys = append(n.keys, key)
+		n.values = append(n.values, value)
+
+		d.skipWhiteSpace()
+		switch c := d.pop(); c {
+		case ',':
+			d.next()
+		case '}':
+			d.next()
+			return n, nil
+		default:
+			return nil, d.error(c, "miss comma or curly bracket")
+		}
+	}
+}
+
+func (d *queryParse) expect(c byte, context string) error {
+	d.skipWhiteSpace()
+	if d.pop() != c {
+		return d.error(d.pop(), context)
+	}
+	d.next()
+	return nil
+}
+
+func isQueryIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isQueryIdent(c byte) bool {
+	return isQueryIdentStart(c) || isDigit(c)
+}
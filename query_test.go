@@ -0,0 +1,144 @@
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+const queryDoc = `{
+	"name": "svc",
+	"items": [
+		{"id": 1, "tags": ["a", "b"], "ok": true},
+		{"id": 2, "tags": [], "ok": false},
+		{"id": 3, "tags": ["c"], "ok": null, "name": "x"}
+	],
+	"meta": {"z": 1, "a": {"b": [10, 20, 30]}}
+}`
+
+// testQuery 把所有输出生成为紧凑 JSON，每个一行
+func testQuery(t *testing.T, expect, src, doc string) {
+	t.Helper()
+	q, err := ParseQuery(src)
+	if err != nil {
+		t.Errorf("ParseQuery %s error %s", src, err.Error())
+		return
+	}
+	v, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for r, err := range q.Run(v) {
+		if err != nil {
+			t.Errorf("query %s error %s", src, err.Error())
+			return
+		}
+		b, err := r.MarshalJSON()
+		if err != nil {
+			t.Errorf("query %s marshal error %s", src, err.Error())
+			return
+		}
+		out = append(out, string(b))
+	}
+	assertEqual(t, expect, strings.Join(out, "\n"))
+}
+
+func testQueryError(t *testing.T, msg, src, doc string) {
+	t.Helper()
+	q, err := ParseQuery(src)
+	if err == nil {
+		v, perr := Parse([]byte(doc))
+		if perr != nil {
+			t.Fatal(perr)
+		}
+		for _, err = range q.Run(v) {
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err == nil {
+		t.Errorf("query %s should fail", src)
+		return
+	}
+	assertEqual(t, msg, err.Error())
+}
+
+func TestQueryPath(t *testing.T) {
+	testQuery(t, `"svc"`, `.name`, queryDoc)
+	testQuery(t, `"svc"`, `."name"`, queryDoc)
+	testQuery(t, `"svc"`, `.["name"]`, queryDoc)
+	testQuery(t, `20`, `.meta.a.b[1]`, queryDoc)
+	testQuery(t, `30`, `.meta.a.b[-1]`, queryDoc)
+	testQuery(t, `null`, `.meta.a.b[5]`, queryDoc)
+	testQuery(t, `null`, `.missing.deeper`, queryDoc)
+	testQuery(t, `10`, `.meta.a.b.[0]`, queryDoc)
+	testQuery(t, `1`, `.meta.z`, queryDoc)
+	testQuery(t, `1`, `.meta[.meta.a.b[0] | (. == 10, .) | select(. == true) | "z"]`, queryDoc)
+}
+
+func TestQueryIterate(t *testing.T) {
+	testQuery(t, "1\n2\n3", `.items[].id`, queryDoc)
+	testQuery(t, "1\n{\"b\":[10,20,30]}", `.meta[]`, queryDoc)
+	testQuery(t, "\"a\"\n\"b\"\n\"c\"", `.items[] | .tags[]`, queryDoc)
+	testQuery(t, "\"svc\"\n1", `.name, .items[0].id`, queryDoc)
+	testQuery(t, "", `.items[1].tags[]`, queryDoc)
+}
+
+func TestQueryFunctions(t *testing.T) {
+	testQuery(t, "1", `.items[] | select(.ok) | .id`, queryDoc)
+	testQuery(t, "2\n3", `.items[] | select(.ok | not) | .id`, queryDoc)
+	testQuery(t, "[2,3]\n[4,2,4,3,4]", `[.items[].id | select(. > 1)], [.items[].id | select(. > 1), 4]`, queryDoc)
+	testQuery(t, `[["a","b"],[],["c"]]`, `.items | map(.tags)`, queryDoc)
+	testQuery(t, `["a","z"]`, `.meta | keys`, queryDoc)
+	testQuery(t, `[0,1,2]`, `.items | keys`, queryDoc)
+	testQuery(t, "3\n3\n2\n0\n1.5", `(.items | length), (.name | length), (.meta | length), (null | length), (-1.5 | length)`, queryDoc)
+	testQuery(t, "2", `"éé" | length`, queryDoc)
+	testQuery(t, "true\nfalse", `(.items[0].ok and .name == "svc"), (.items[1].ok or null)`, queryDoc)
+}
+
+func TestQueryConstruct(t *testing.T) {
+	testQuery(t, `{"name":"svc","n":3}`, `{name, "n": (.items | length)}`, queryDoc)
+	testQuery(t, "{\"svc\":1}\n{\"svc\":2}", `{(.name): (.items[0].id, .items[1].id)}`, queryDoc)
+	testQuery(t, `[{"id":1,"t":2},{"id":2,"t":0},{"id":3,"t":1}]`, `[.items[] | {id, t: (.tags | length)}]`, queryDoc)
+	testQuery(t, `[]`, `[]`, queryDoc)
+	testQuery(t, `{}`, `{}`, queryDoc)
+	testQuery(t, `[1.50,"a",true,false,null]`, `[1.50, "a", true, false, null]`, queryDoc)
+}
+
+func TestQueryCompare(t *testing.T) {
+	testQuery(t, "true\ntrue\ntrue\ntrue\ntrue\ntrue", `(null < false), (false < true), (true < 0), (1 < "a"), ("a" < []), ([] < {})`, `null`)
+	testQuery(t, "true\nfalse\ntrue\ntrue", `([1,2] < [1,3]), ([1,2] > [1,2,0]), ({"a":1} == {"a":1.0}), ({"a":2} < {"b":1})`, `null`)
+	testQuery(t, "true\ntrue\nfalse\ntrue", `(.a != .b), (.a <= 1), (.a >= 2), ("b" > "a")`, `{"a":1,"b":2}`)
+}
+
+func TestQueryError(t *testing.T) {
+	testQueryError(t, "invalid character \x00 miss term", `.a |`, `null`)
+	testQueryError(t, "invalid character \x00 miss square bracket", `.a[1`, `null`)
+	testQueryError(t, "unknown function first", `first`, `null`)
+	testQueryError(t, "invalid character ) unexpected token", `.a)`, `null`)
+	testQueryError(t, "invalid character } miss colon", `{(.a)}`, `null`)
+	testQueryError(t, "cannot index number with string", `.a.b`, `{"a":1}`)
+	testQueryError(t, "cannot iterate over null", `.a[]`, `{}`)
+	testQueryError(t, "boolean has no length", `length`, `true`)
+	testQueryError(t, "object key must be a string, not number", `{(.a): 1}`, `{"a":1}`)
+
+	q, err := ParseQuery(`.a | first`)
+	assertTrue(t, q == nil)
+	se, ok := err.(*SyntaxError)
+	assertTrue(t, ok && se.Offset == 5)
+}
+
+func TestQueryStop(t *testing.T) {
+	q, _ := ParseQuery(`.[]`)
+	v, _ := Parse([]byte(`[1,2,3]`))
+	n := 0
+	for range q.Run(v) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	assertEqual(t, 2, n)
+	assertEqual(t, ".[]", q.String())
+}
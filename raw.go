@@ -0,0 +1,101 @@
+package json
+
+import (
+	"errors"
+	"reflect"
+)
+
+// RawMessage is a raw encoded JSON value. It can be used to delay
+// decoding part of a message or to precompute an encoding.
+//
+// Unmarshal stores in a RawMessage a copy of the exact bytes of the
+// value in the input, white space inside the value included. Marshal
+// checks that a RawMessage is valid JSON and writes it out compacted,
+// with insignificant white space removed and all other bytes, such as
+// string escapes and number text, unchanged; a nil RawMessage encodes
+// as null.
+type RawMessage []byte
+
+// MarshalJSON returns m as the JSON encoding of m.
+func (m RawMessage) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	return m, nil
+}
+
+// UnmarshalJSON sets *m to a copy of data.
+func (m *RawMessage) UnmarshalJSON(data []byte) error {
+	if m == nil {
+		return errors.New("json.RawMessage: UnmarshalJSON on nil pointer")
+	}
+	*m = append((*m)[0:0], data...)
+	return nil
+}
+
+var rawMessageType = reflect.TypeFor[RawMessage]()
+
+// rawMessageEncoder 校验后去掉空白写入，不经过 MarshalJSON 的解析，字符串和数字保持原文
+func rawMessageEncoder(e *encodeState, v reflect.Value) error {
+	m := v.Interface().(RawMessage)
+	if m == nil {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+	spaced, err := checkValue(m)
+	if err != nil {
+		return &MarshalerError{rawMessageType, err, "MarshalJSON"}
+	}
+	if spaced {
+		e.buf = appendCompact(e.buf, m)
+	} else {
+		e.buf = append(e.buf, m...)
+	}
+	return nil
+}
+
+// checkValue 校验 data 恰好是一个 JSON 值，前后可以有空白，不生成 jsonValue；
+// spaced 表示字符串以外有空白，需要压缩
+func checkValue(data []byte) (spaced bool, err error) {
+	d := new(jsonParse)
+	d.init(data)
+	d.skipWhiteSpace()
+	err = d.skipValue()
+	if d.skipWhiteSpace(); err == nil && d.off < len(data) {
+		err = &SyntaxError{msg: "unexpected end of JSON input", Offset: d.off}
+	}
+	return d.spaced, err
+}
+
+// appendCompact 去掉已校验的 src 中字符串以外的空白，其余字节原样追加到 dst
+func appendCompact(dst, src []byte) []byte {
+	inString := false
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case inString && c == '\\':
+			dst = append(dst, c, src[i+1])
+			i++
+			continue
+		case c == '"':
+			inString = !inString
+		case !inString && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			continue
+		}
+		dst = append(dst, c)
+	}
+	return dst
+}
+
+// rawMessageDecoder 保存值在输入中的原文，没有原文的值（例如查询的结果）使用压缩后的文本
+func rawMessageDecoder(d *decodeState, jv *jsonValue, v reflect.Value) error {
+	text := jv.text
+	if text == nil {
+		var err error
+		if text, err = appendValue(nil, jv); err != nil {
+			return err
+		}
+	}
+	v.SetBytes(append(v.Bytes()[0:0], text...))
+	return nil
+}
@@ -0,0 +1,76 @@
+package json
+
+import (
+	"errors"
+	"testing"
+)
+
+type envelope struct {
+	Type    string      `json:"type"`
+	Payload RawMessage  `json:"payload"`
+	Meta    *RawMessage `json:"meta,omitempty"`
+}
+
+func TestUnmarshalRawMessage(t *testing.T) {
+	data := []byte(`{"type":"point", "payload": { "x" : 1.50,  "y":[ 2 ] } , "meta":null}`)
+	var e envelope
+	if err := Unmarshal(data, &e); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, `{ "x" : 1.50,  "y":[ 2 ] }`, string(e.Payload))
+	assertTrue(t, e.Meta == nil)
+	// 保存的是拷贝，不引用输入
+	data[30] = 'z'
+	assertEqual(t, `{ "x" : 1.50,  "y":[ 2 ] }`, string(e.Payload))
+
+	if err := Unmarshal([]byte(`{"payload":"ab","meta":[1, 2]}`), &e); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, `"ab"`, string(e.Payload))
+	assertEqual(t, `[1, 2]`, string(*e.Meta))
+
+	var list []RawMessage
+	if err := Unmarshal([]byte(`[1e2, null, {"a": true}]`), &list); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, []RawMessage{RawMessage(`1e2`), RawMessage(`null`), RawMessage(`{"a": true}`)}, list)
+
+	// 按需解析和 Decoder 同样保留原文
+	v, err := parseLazyJson(t, []byte(`{"p": { "a" : [ 1 ] }}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Get[RawMessage](v, "/p/a")
+	assertTrue(t, err == nil)
+	assertEqual(t, `[ 1 ]`, string(p))
+	dec := NewBytesDecoder([]byte(`{"type":"a","payload":[ true ]} {"payload": 2 }`))
+	for _, want := range []string{`[ true ]`, `2`} {
+		var e envelope
+		assertTrue(t, dec.Decode(&e) == nil)
+		assertEqual(t, want, string(e.Payload))
+	}
+}
+
+func TestMarshalRawMessage(t *testing.T) {
+	meta := RawMessage(`{ "k" : 1 }`)
+	testMarshal(t, `{"type":"t","payload":[1,2],"meta":{"k":1}}`, envelope{Type: "t", Payload: RawMessage(`[ 1, 2 ]`), Meta: &meta})
+	// 只去掉空白，字符串里的空白和转义以及数字的原文不变
+	testMarshal(t, `{"a b":"\u00e9\"\\ x","n":1.50E+2}`, RawMessage("\n{ \"a b\" :\t\"\\u00e9\\\"\\\\ x\",\r\n \"n\": 1.50E+2 }\n"))
+	testMarshal(t, `{"type":"","payload":null}`, envelope{})
+	testMarshal(t, `[1.0]`, []interface{}{RawMessage(`1.0`)})
+
+	_, err := Marshal(envelope{Payload: RawMessage(`{"a":}`)})
+	var me *MarshalerError
+	assertTrue(t, errors.As(err, &me))
+	assertEqual(t, "json: error calling MarshalJSON for type json.RawMessage: invalid character } number syntax invalid", err.Error())
+	_, err = Marshal(RawMessage(`1 2`))
+	assertTrue(t, errors.As(err, &me))
+
+	// 兼容 encoding/json.RawMessage 的方法
+	b, err := RawMessage(nil).MarshalJSON()
+	assertTrue(t, err == nil)
+	assertEqual(t, "null", string(b))
+	var m RawMessage
+	assertTrue(t, m.UnmarshalJSON([]byte(`[]`)) == nil)
+	assertEqual(t, "[]", string(m))
+}
@@ -0,0 +1,220 @@
+package json
+
+import "strconv"
+
+// A Diagnostic describes one syntax error found by ParseRecover.
+type Diagnostic struct {
+	Offset int // byte offset in the input
+	Line   int // 1-based line number
+	Column int // 1-based column, counted in characters
+	Msg    string
+}
+
+func (d Diagnostic) String() string {
+	return strconv.Itoa(d.Line) + ":" + strconv.Itoa(d.Column) + ": " + d.Msg
+}
+
+// ParseRecover parses data like Parse but does not stop at the first
+// syntax error. It resynchronizes after each error and returns a
+// best-effort tree, in which the parts that could not be parsed are
+// ValueInvalid nodes, together with every diagnostic in input order.
+func ParseRecover(data []byte) (*Value, []Diagnostic) {
+	d := &recoverParse{}
+	d.init(data)
+	d.skipWhiteSpace()
+	v := d.recValue()
+	d.skipWhiteSpace()
+	if d.off < len(d.data) {
+		d.report(&SyntaxError{msg: "unexpected end of JSON input", Offset: d.off})
+	}
+	return v, d.diags
+}
+
+// recoverParse 是容错解析器：标量复用 jsonParse，数组和对象在出错后重新同步
+type recoverParse struct {
+	jsonParse
+	diags []Diagnostic
+}
+
+func (d *recoverParse) report(err error) {
+	off := d.off
+	if se, ok := err.(*SyntaxError); ok {
+		off = se.Offset
+	}
+	line, col := LineCol(d.data, off)
+	d.diags = append(d.diags, Diagnostic{Offset: off, Line: line, Column: col, Msg: err.Error()})
+}
+
+func (d *recoverParse) invalid() *jsonValue {
+	return &jsonValue{valueType: ValueInvalid}
+}
+
+func (d *recoverParse) recValue() *jsonValue {
+	start := d.off
+	var v *jsonValue
+	switch c := d.pop(); c {
+	case '[':
+		v = d.recArray()
+	case '{':
+		v = d.recObject()
+	case ',', ']', '}', 0:
+		// 缺少值，不消费分隔符，交给外层处理
+		d.report(d.error(c, "number syntax invalid"))
+		v = d.invalid()
+	default:
+		var err error
+		if v, err = d.parserValue(); err != nil {
+			d.report(err)
+			d.sync(start)
+			v = d.invalid()
+		}
+	}
+	// 无法解析的部分也记录跳过的区间
+	v.start, v.end = start, d.off
+	return v
+}
+
+// sync 跳过出错的标量：字符串跳到结束引号或行尾，其他跳到下一个分隔符
+func (d *recoverParse) sync(start int) {
+	if d.data[start] == '"' {
+		for d.off = start + 1; d.off < len(d.data); d.off++ {
+			switch d.data[d.off] {
+			case '\\':
+				d.off++
+			case '"':
+				d.off++
+				return
+			case '\n':
+				return
+			}
+		}
+		return
+	}
+	if d.off == start {
+		d.off++
+	}
+	for d.off < len(d.data) && !isDelimiter(d.data[d.off]) {
+		d.off++
+	}
+}
+
+func (d *recoverParse) recArray() *jsonValue {
+	v := &jsonValue{valueType: ValueArray}
+	d.next()
+	for {
+		d.skipWhiteSpace()
+		switch c := d.pop(); c {
+		case ']':
+			d.next()
+			return v
+		case 0, '}':
+			// 缺少 ]，'}' 留给外层对象
+			d.report(d.error(c, "MISS_COMMA_OR_SQUARE_BRACKET"))
+			return v
+		case ',', ':':
+			d.report(d.error(c, "number syntax invalid"))
+			d.next()
+			continue
+		}
+		v.array.values = append(v.array.values, d.recValue())
+		v.array.len = len(v.array.values)
+
+		d.skipWhiteSpace()
+		switch c := d.pop(); c {
+		case ',':
+			d.next()
+			d.skipWhiteSpace()
+			if c = d.pop(); c == ']' {
+				d.report(d.error(c, "number syntax invalid"))
+			}
+		case ']':
+		case 0, '}':
+			d.report(d.error(c, "MISS_COMMA_OR_SQUARE_BRACKET"))
+			return v
+		default:
+			// 缺少逗号，继续解析下一个值
+			d.report(d.error(c, "MISS_COMMA_OR_SQUARE_BRACKET"))
+		}
+	}
+}
+
+func (d *recoverParse) recObject() *jsonValue {
+	v := &jsonValue{valueType: ValueObject}
+	d.next()
+	for {
+		d.skipWhiteSpace()
+		c := d.pop()
+		switch c {
+		case '}':
+			d.next()
+			return v
+		case 0, ']':
+			// 缺少 }，']' 留给外层数组
+			d.report(d.error(c, "miss comma or curly bracket"))
+			return v
+		case ',', ':':
+			d.report(d.error(c, "miss key"))
+			d.next()
+			continue
+		}
+		// 解析key
+		var key *jsonValue
+		start := d.off
+		if c == '"' {
+			key = &jsonValue{}
+			if err := d.parseString(key); err != nil {
+				d.report(err)
+				d.sync(start)
+				key = d.invalid()
+			}
+		} else {
+			d.report(d.error(c, "miss key"))
+			if c == '[' || c == '{' {
+				d.recValue()
+			} else {
+				d.sync(start)
+			}
+			key = d.invalid()
+		}
+		key.start, key.end = start, d.off
+		// 解析 ：字符，缺少时如果后面像是一个值就照常解析
+		d.skipWhiteSpace()
+		colon := d.pop() == ':'
+		if colon {
+			d.next()
+			d.skipWhiteSpace()
+		} else {
+			d.report(d.error(d.pop(), "miss colon"))
+		}
+		var value *jsonValue
+		if c = d.pop(); c == ',' || c == '}' || c == ']' || c == 0 {
+			if colon {
+				d.report(d.error(c, "number syntax invalid"))
+			}
+			value = d.invalid()
+		} else {
+			value = d.recValue()
+		}
+		v.object.keys = append(v.object.keys, key)
+		v.object.values = append(v.object.values, value)
+		v.object.size = len(v.object.values)
+
+		// 解析分隔符、结束符
+		d.skipWhiteSpace()
+		switch c = d.pop(); c {
+		case ',':
+			d.next()
+			d.skipWhiteSpace()
+			if c = d.pop(); c == '}' {
+				d.report(d.error(c, "miss key"))
+			}
+		case '}':
+		case 0, ']':
+			d.report(d.error(c, "miss comma or curly bracket"))
+			return v
+		default:
+			// 缺少逗号，继续解析下一个成员
+			d.report(d.error(c, "miss comma or curly bracket"))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package json
+
+import "testing"
+
+func testRecover(t *testing.T, source string, expect []string) *Value {
+	t.Helper()
+	v, diags := ParseRecover([]byte(source))
+	var got []string
+	for _, d := range diags {
+		got = append(got, d.String())
+	}
+	assertEqual(t, expect, got)
+	return v
+}
+
+func TestParseRecoverValid(t *testing.T) {
+	v := testRecover(t, `{"a": [1, 2], "b": {"c": null}}`, nil)
+	assertEqual(t, 2, v.getObjectSize())
+}
+
+func TestParseRecoverObject(t *testing.T) {
+	v := testRecover(t, "{\n  \"a\": 1\n  \"b\" 2,\n  \"c\": tru,\n  \"d\": [1 2],\n}", []string{
+		`3:3: invalid character " miss comma or curly bracket`,
+		"3:7: invalid character 2 miss colon",
+		"4:11: invalid character e parseJson type 2 error",
+		"5:11: invalid character 2 MISS_COMMA_OR_SQUARE_BRACKET",
+		"6:1: invalid character } miss key",
+	})
+	assertEqual(t, 4, v.getObjectSize())
+	var keys []string
+	for k := range v.Members() {
+		keys = append(keys, k)
+	}
+	assertEqual(t, []string{"a", "b", "c", "d"}, keys)
+	b, _ := v.getObjectValue(1)
+	n, _ := b.getNumber()
+	assertEqual(t, 2.0, n)
+	c, _ := v.getObjectValue(2)
+	assertTrue(t, c.getValueType() == ValueInvalid)
+	d, _ := v.getObjectValue(3)
+	assertEqual(t, 2, d.getArrayLen())
+}
+
+func TestParseRecoverArray(t *testing.T) {
+	v := testRecover(t, `[1,,"x" ?, 3,]`, []string{
+		"1:4: invalid character , number syntax invalid",
+		"1:9: invalid character ? MISS_COMMA_OR_SQUARE_BRACKET",
+		"1:9: invalid character ? number syntax invalid",
+		"1:14: invalid character ] number syntax invalid",
+	})
+	assertEqual(t, 4, v.getArrayLen())
+	e, _ := v.getArrayElem(1)
+	s, _ := e.getString()
+	assertEqual(t, "x", s)
+
+	v = testRecover(t, `{"a": [1, 2}`, []string{"1:12: invalid character } MISS_COMMA_OR_SQUARE_BRACKET"})
+	a, _ := v.getObjectValue(0)
+	assertEqual(t, 2, a.getArrayLen())
+}
+
+func TestParseRecoverUnterminated(t *testing.T) {
+	testRecover(t, "{\"a\": \"abc\n, \"b\": 1", []string{
+		"1:11: invalid character \n invalid string char",
+		"2:9: invalid character \x00 miss comma or curly bracket",
+	})
+	testRecover(t, "[1] 2", []string{"1:5: unexpected end of JSON input"})
+}
@@ -0,0 +1,146 @@
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// RFC 7464 JSON Text Sequences（application/json-seq）：
+// 每条记录以 RS（0x1E）开头、以 LF 结尾。
+
+const recordSeparator = 0x1E
+
+var errTruncated = errors.New("truncated record")
+
+// A SeqError records a malformed or truncated record of a JSON text sequence.
+type SeqError struct {
+	Record    int  // 1-based record number
+	Truncated bool // the record was cut off before its terminating LF
+	Err       error
+}
+
+func (e *SeqError) Error() string {
+	return "record " + strconv.Itoa(e.Record) + ": " + e.Err.Error()
+}
+
+func (e *SeqError) Unwrap() error { return e.Err }
+
+// A SeqReader reads a JSON text sequence. Malformed and truncated
+// records are skipped as RFC 7464 recommends; their errors are
+// available from Errors.
+type SeqReader struct {
+	r      *bufio.Reader
+	record int
+	start  bool // 已经读到第一个 RS
+	value  *Value
+	err    error
+	errs   []error
+}
+
+// NewSeqReader returns a SeqReader that reads from r.
+func NewSeqReader(r io.Reader) *SeqReader {
+	return &SeqReader{r: bufio.NewReader(r)}
+}
+
+// Next advances to the next well-formed record. It returns false at
+// the end of the input or on a read error.
+func (sr *SeqReader) Next() bool {
+	sr.value = nil
+	for sr.err == nil {
+		text, err := sr.r.ReadBytes(recordSeparator)
+		if err != nil && err != io.EOF {
+			sr.err = err
+			return false
+		}
+		eof := err == io.EOF
+		if !eof {
+			text = text[:len(text)-1]
+		}
+		if !sr.start {
+			// 第一个 RS 之前只允许出现空白
+			sr.start = true
+			if len(bytes.TrimSpace(text)) != 0 {
+				sr.record++
+				sr.errs = append(sr.errs, &SeqError{Record: sr.record, Err: &SyntaxError{msg: "missing record separator"}})
+			}
+		} else if len(text) > 0 {
+			// 连续的 RS 产生的空记录直接忽略
+			sr.record++
+			if v, err := sr.parseRecord(text); err != nil {
+				sr.errs = append(sr.errs, err)
+			} else {
+				sr.value = v
+				return true
+			}
+		}
+		if eof {
+			return false
+		}
+	}
+	return false
+}
+
+// parseRecord 解析一条记录，并按 RFC 7464 2.4 检查截断
+func (sr *SeqReader) parseRecord(text []byte) (*Value, error) {
+	v, err := Parse(text)
+	if err != nil {
+		return nil, &SeqError{Record: sr.record, Truncated: !bytes.HasSuffix(text, []byte("\n")), Err: err}
+	}
+	// 顶层为数字或 true/false/null 时，末尾没有空白就无法确认是否被截断
+	switch v.getValueType() {
+	case ValueNumber, ValueTrue, ValueFalse, ValueNull:
+		if c := text[len(text)-1]; c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return nil, &SeqError{Record: sr.record, Truncated: true, Err: errTruncated}
+		}
+	}
+	return v, nil
+}
+
+// Value returns the record read by the last call to Next.
+func (sr *SeqReader) Value() *Value { return sr.value }
+
+// Record returns the 1-based number of the current record.
+func (sr *SeqReader) Record() int { return sr.record }
+
+// Decode stores the current record in the value pointed to by v.
+func (sr *SeqReader) Decode(v interface{}) error {
+	if sr.value == nil {
+		return &SeqError{Record: sr.record, Err: io.ErrUnexpectedEOF}
+	}
+	if err := unmarshalValue(sr.value, v); err != nil {
+		return &SeqError{Record: sr.record, Err: err}
+	}
+	return nil
+}
+
+// Err returns the read error that stopped the reader, if any.
+func (sr *SeqReader) Err() error { return sr.err }
+
+// Errors returns the errors of the skipped records.
+func (sr *SeqReader) Errors() []error { return sr.errs }
+
+// A SeqWriter writes a JSON text sequence.
+type SeqWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewSeqWriter returns a SeqWriter that writes to w.
+func NewSeqWriter(w io.Writer) *SeqWriter {
+	return &SeqWriter{w: w}
+}
+
+// Encode writes v as one record: RS, the compact JSON encoding of v, LF.
+func (sw *SeqWriter) Encode(v interface{}) error {
+	e := &encodeState{buf: append(sw.buf[:0], recordSeparator)}
+	if err := e.reflectValue(reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	sw.buf = append(e.buf, '\n')
+	_, err := sw.w.Write(sw.buf)
+	return err
+}
@@ -0,0 +1,74 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSeqReader(t *testing.T) {
+	input := "\x1e{\"a\":1}\n\x1e\x1e[1,2]\n\x1e\"s\"\n\x1e12\n"
+	sr := NewSeqReader(strings.NewReader(input))
+	var out []string
+	for sr.Next() {
+		b, err := Marshal(sr.Value())
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, string(b))
+	}
+	assertTrue(t, sr.Err() == nil)
+	assertEqual(t, 0, len(sr.Errors()))
+	assertEqual(t, []string{`{"a":1}`, `[1,2]`, `"s"`, `12`}, out)
+	assertEqual(t, 4, sr.Record())
+}
+
+func TestSeqReaderTruncated(t *testing.T) {
+	// 第 1 条被截断，第 2 条数字缺少结尾 LF，第 3 条语法错误，第 4 条正常
+	input := "\x1e{\"a\":\x1e123\x1e[1 2]\n\x1etrue\n\x1e456"
+	sr := NewSeqReader(strings.NewReader(input))
+	var values []bool
+	for sr.Next() {
+		var b bool
+		if err := sr.Decode(&b); err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, b)
+	}
+	assertEqual(t, []bool{true}, values)
+
+	errs := sr.Errors()
+	assertEqual(t, 4, len(errs))
+	var serr *SeqError
+	assertTrue(t, errors.As(errs[0], &serr) && serr.Record == 1 && serr.Truncated)
+	assertTrue(t, errors.As(errs[1], &serr) && serr.Record == 2 && serr.Truncated)
+	assertTrue(t, errors.As(errs[2], &serr) && serr.Record == 3 && !serr.Truncated)
+	assertTrue(t, errors.As(errs[3], &serr) && serr.Record == 5 && serr.Truncated)
+}
+
+func TestSeqReaderLeadingGarbage(t *testing.T) {
+	sr := NewSeqReader(strings.NewReader("junk\x1e1\n"))
+	assertTrue(t, sr.Next())
+	assertEqual(t, 1, len(sr.Errors()))
+}
+
+func TestSeqWriter(t *testing.T) {
+	var buf bytes.Buffer
+	sw := NewSeqWriter(&buf)
+	if err := sw.Encode(map[string]string{"k": "line\nbreak"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.Encode(42); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "\x1e{\"k\":\"line\\nbreak\"}\n\x1e42\n", buf.String())
+
+	sr := NewSeqReader(&buf)
+	count := 0
+	for sr.Next() {
+		count++
+	}
+	assertEqual(t, 2, count)
+	assertEqual(t, 0, len(sr.Errors()))
+}
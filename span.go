@@ -0,0 +1,45 @@
+package json
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// Span returns the byte offsets of v in the input it was parsed from,
+// so that the text of v is data[start:end]. Offsets are relative to
+// the whole input, including for values inside a lazily parsed array
+// or object. Values that were not parsed from input, such as those
+// constructed by a Query, report 0, 0.
+func (v *jsonValue) Span() (start, end int) {
+	return v.start, v.end
+}
+
+// KeySpan returns the byte offsets of the name of the i-th member of
+// the object v, in the order yielded by Members, including the quotes.
+// It reports 0, 0 if v is not an object or has no i-th member.
+func (v *jsonValue) KeySpan(i int) (start, end int) {
+	if i < 0 {
+		return 0, 0
+	}
+	k, err := v.getObjectKey(i)
+	if err != nil {
+		return 0, 0
+	}
+	return k.start, k.end
+}
+
+// LineCol converts the byte offset off in data, such as a Span offset
+// or SyntaxError.Offset, to a 1-based line and column. Columns count
+// characters, not bytes. Offsets outside data are clamped to its
+// start or end.
+func LineCol(data []byte, off int) (line, col int) {
+	if off < 0 {
+		off = 0
+	} else if off > len(data) {
+		off = len(data)
+	}
+	head := data[:off]
+	line = bytes.Count(head, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(head, '\n') + 1
+	return line, utf8.RuneCount(head[lineStart:]) + 1
+}
@@ -0,0 +1,114 @@
+package json
+
+import "testing"
+
+// spans 按 JSON Pointer 收集每个值在 data 中对应的原文
+func spans(data []byte, v *Value) map[string]string {
+	m := map[string]string{}
+	for path, e := range v.All() {
+		start, end := e.Span()
+		m[path] = string(data[start:end])
+	}
+	return m
+}
+
+func TestSpan(t *testing.T) {
+	data := []byte("{\n  \"a\": [1, \"x\", {\"b\" : null}],\n  \"c\": true }")
+	want := map[string]string{
+		"":       string(data),
+		"/a":     `[1, "x", {"b" : null}]`,
+		"/a/0":   `1`,
+		"/a/1":   `"x"`,
+		"/a/2":   `{"b" : null}`,
+		"/a/2/b": `null`,
+		"/c":     `true`,
+	}
+	v, err := Parse(data)
+	assertTrue(t, err == nil)
+	assertEqual(t, want, spans(data, v))
+
+	// 按需解析时子节点的偏移仍然相对于整个输入
+	v, err = parseLazyJson(t, data)
+	assertTrue(t, err == nil)
+	assertEqual(t, want, spans(data, v))
+
+	v, err = parseIndexed(data)
+	assertTrue(t, err == nil)
+	assertEqual(t, want, spans(data, v))
+
+	v, diags := ParseRecover(data)
+	assertEqual(t, 0, len(diags))
+	assertEqual(t, want, spans(data, v))
+
+	data = []byte("{a: 'x', // c\n b: [1,],}")
+	v, err = ParseJSON5(data)
+	assertTrue(t, err == nil)
+	assertEqual(t, map[string]string{"": string(data), "/a": `'x'`, "/b": `[1,]`, "/b/0": `1`}, spans(data, v))
+
+	data = []byte(`1 {"a": 2}`)
+	docs, err := ParseAll(data)
+	assertTrue(t, err == nil)
+	start, end := docs[1].Value.Span()
+	assertEqual(t, []int{2, 10}, []int{start, end})
+	a, err := Get[*Value](docs[1].Value, "/a")
+	assertTrue(t, err == nil)
+	start, end = a.Span()
+	assertEqual(t, []int{8, 9}, []int{start, end})
+
+	// 查询构造的值不来自输入
+	q, err := ParseQuery("[.[]]")
+	assertTrue(t, err == nil)
+	v, _ = Parse([]byte(`[1]`))
+	for r, err := range q.Run(v) {
+		assertTrue(t, err == nil)
+		start, end = r.Span()
+		assertEqual(t, []int{0, 0}, []int{start, end})
+	}
+}
+
+func TestKeySpan(t *testing.T) {
+	data := []byte(`{"a": 1, "b\"c" : {"d":2}}`)
+	keys := func(v *Value) []string {
+		var s []string
+		for i := 0; i <= v.getObjectSize(); i++ {
+			start, end := v.KeySpan(i)
+			s = append(s, string(data[start:end]))
+		}
+		return s
+	}
+	v, err := Parse(data)
+	assertTrue(t, err == nil)
+	assertEqual(t, []string{`"a"`, `"b\"c"`, ``}, keys(v))
+	v, err = parseLazyJson(t, data)
+	assertTrue(t, err == nil)
+	e, err := v.getObjectValue(1)
+	assertTrue(t, err == nil)
+	assertEqual(t, []string{`"d"`, ``}, keys(e))
+	v, err = parseIndexed(data)
+	assertTrue(t, err == nil)
+	assertEqual(t, []string{`"a"`, `"b\"c"`, ``}, keys(v))
+
+	// 容错解析时无法解析的 key 记录跳过的区间
+	data = []byte(`{"a": 1, x: 2}`)
+	v, _ = ParseRecover(data)
+	assertEqual(t, []string{`"a"`, `x`, ``}, keys(v))
+
+	start, end := v.KeySpan(-1)
+	assertEqual(t, []int{0, 0}, []int{start, end})
+	start, end = v.object.values[0].KeySpan(0)
+	assertEqual(t, []int{0, 0}, []int{start, end})
+}
+
+func TestLineCol(t *testing.T) {
+	data := []byte("ab\n中文x\n")
+	line, col := LineCol(data, 0)
+	assertEqual(t, []int{1, 1}, []int{line, col})
+	line, col = LineCol(data, 9)
+	assertEqual(t, []int{2, 3}, []int{line, col})
+	line, col = LineCol(data, 11)
+	assertEqual(t, []int{3, 1}, []int{line, col})
+	line, col = LineCol(data, -1)
+	assertEqual(t, []int{1, 1}, []int{line, col})
+	line, col = LineCol(data, 100)
+	assertEqual(t, []int{3, 1}, []int{line, col})
+}
@@ -0,0 +1,338 @@
+package json
+
+import (
+	"bytes"
+	"math/bits"
+)
+
+// 两阶段解析（参考 simdjson）：
+// stage 1 以 64 字节为一块，用 SWAR 方式一次处理 8 个字节，
+// 计算引号、转义、字符串区间的位图，得到所有结构字符和标量起始位置的索引；
+// stage 2 只沿着索引前进，不再逐字节扫描空白和分隔符。
+
+const (
+	swarOnes  = 0x0101010101010101
+	swarHighs = 0x8080808080808080
+	swarLows  = 0x7f7f7f7f7f7f7f7f
+	swarGroup = 0x0002040810204081 // 把每个字节的最高位收集到高 8 位
+)
+
+// swarEqual 返回 x 中等于 b 的字节，结果为每字节 1 位的 8 位掩码
+func swarEqual(x uint64, b byte) uint64 {
+	y := x ^ (swarOnes * uint64(b))
+	t := ((y & swarLows) + swarLows) | y
+	return ((^t & swarHighs) * swarGroup) >> 56
+}
+
+// blockMasks 计算 64 字节块中引号、反斜杠、结构字符和空白的位图
+type blockMasks struct {
+	quote     uint64
+	backslash uint64
+	op        uint64
+	space     uint64
+}
+
+func loadBlock(block []byte) blockMasks {
+	var m blockMasks
+	for i := 0; i < 64; i += 8 {
+		x := uint64(block[i]) | uint64(block[i+1])<<8 | uint64(block[i+2])<<16 | uint64(block[i+3])<<24 |
+			uint64(block[i+4])<<32 | uint64(block[i+5])<<40 | uint64(block[i+6])<<48 | uint64(block[i+7])<<56
+		m.quote |= swarEqual(x, '"') << i
+		m.backslash |= swarEqual(x, '\\') << i
+		m.op |= (swarEqual(x, '{') | swarEqual(x, '}') | swarEqual(x, '[') | swarEqual(x, ']') |
+			swarEqual(x, ':') | swarEqual(x, ',')) << i
+		m.space |= (swarEqual(x, ' ') | swarEqual(x, '\t') | swarEqual(x, '\n') | swarEqual(x, '\r')) << i
+	}
+	return m
+}
+
+// prefixXor 计算前缀异或，用于把引号位置转换成字符串区间
+func prefixXor(x uint64) uint64 {
+	x ^= x << 1
+	x ^= x << 2
+	x ^= x << 4
+	x ^= x << 8
+	x ^= x << 16
+	x ^= x << 32
+	return x
+}
+
+// stage1 跨块状态
+type stage1 struct {
+	escaped  uint64 // 上一块末尾的反斜杠转义了本块第一个字节
+	inString uint64 // 上一块结束时仍在字符串内则为全 1
+	scalar   uint64 // 上一块最后一个字节属于标量
+	indices  []uint32
+}
+
+// buildIndex 生成结构索引：{}[]:, 的位置、字符串开头的引号位置以及标量的起始位置
+func buildIndex(data []byte) ([]uint32, error) {
+	s := &stage1{indices: make([]uint32, 0, len(data)/8)}
+	var pad [64]byte
+	i := 0
+	for ; i+64 <= len(data); i += 64 {
+		s.block(loadBlock(data[i:i+64]), uint32(i))
+	}
+	if i < len(data) {
+		n := copy(pad[:], data[i:])
+		for j := n; j < 64; j++ {
+			pad[j] = ' '
+		}
+		s.block(loadBlock(pad[:]), uint32(i))
+	}
+	if s.inString != 0 {
+		return nil, &SyntaxError{msg: "invalid character " + string(byte(0)) + " miss quotation mark", Offset: len(data)}
+	}
+	return s.indices, nil
+}
+
+func (s *stage1) block(m blockMasks, base uint32) {
+	// 计算被转义的字节
+	escaped := s.escaped
+	s.escaped = 0
+	for bs := m.backslash; bs != 0; bs &= bs - 1 {
+		bit := bs & -bs
+		if escaped&bit != 0 {
+			continue
+		}
+		if bit == 1<<63 {
+			s.escaped = 1
+		} else {
+			escaped |= bit << 1
+		}
+	}
+	quote := m.quote &^ escaped
+	inString := prefixXor(quote) ^ s.inString
+	s.inString = uint64(int64(inString) >> 63)
+
+	// 标量：既不是空白、结构字符、引号，也不在字符串内
+	scalar := ^(m.op | m.space | quote | inString)
+	scalarStart := scalar &^ (scalar<<1 | s.scalar)
+	s.scalar = scalar >> 63
+
+	structural := m.op&^inString | quote&inString | scalarStart
+	for ; structural != 0; structural &= structural - 1 {
+		s.indices = append(s.indices, base+uint32(bits.TrailingZeros64(structural)))
+	}
+}
+
+// indexParse 是 stage 2：沿着结构索引构建 jsonValue。
+// 节点数可以从索引直接数出来，节点、子节点指针和字符串内容都一次性分配再切分，
+// 避免逐个分配小对象
+type indexParse struct {
+	jsonParse
+	indices []uint32
+	pos     int          // 下一个待处理的索引
+	nodes   []jsonValue  // 尚未使用的节点
+	ptrs    []*jsonValue // 尚未使用的子节点指针
+	stack   []*jsonValue // 正在解析的数组和对象已有的子节点，对象按 key、value 交替存放
+	strs    []byte       // 字符串内容
+}
+
+func parseIndexed(data []byte) (*jsonValue, error) {
+	indices, err := buildIndex(data)
+	if err != nil {
+		return nil, err
+	}
+	d := &indexParse{indices: indices}
+	d.init(data)
+	if len(indices) == 0 {
+		return nil, d.error(0, "number syntax invalid")
+	}
+	// 除分隔符和结束括号外，每个索引都是一个值或 key 的开头
+	n := 0
+	for _, i := range indices {
+		switch data[i] {
+		case ',', ':', ']', '}':
+		default:
+			n++
+		}
+	}
+	if n == 0 {
+		// 只有分隔符或结束括号，例如 "]"
+		return nil, d.error(data[indices[0]], "number syntax invalid")
+	}
+	d.nodes = make([]jsonValue, n)
+	d.ptrs = make([]*jsonValue, n-1)
+	d.strs = make([]byte, 0, len(data))
+	v, err := d.indexValue()
+	if err != nil {
+		return v, err
+	}
+	if d.pos != len(d.indices) {
+		return v, &SyntaxError{msg: "unexpected end of JSON input", Offset: d.off}
+	}
+	return v, nil
+}
+
+// newValue 从预先分配的节点中取出一个
+func (d *indexParse) newValue() *jsonValue {
+	if len(d.nodes) == 0 {
+		// 只有输入有错时才会用完
+		return &jsonValue{}
+	}
+	v := &d.nodes[0]
+	d.nodes = d.nodes[1:]
+	return v
+}
+
+// children 取出 stack[base:] 中每隔 step 个的子节点，放入预先分配的指针中
+func (d *indexParse) children(base, off, step int) []*jsonValue {
+	n := (len(d.stack) - base) / step
+	var c []*jsonValue
+	if n <= len(d.ptrs) {
+		c, d.ptrs = d.ptrs[:n:n], d.ptrs[n:]
+	} else {
+		c = make([]*jsonValue, n)
+	}
+	for i := range c {
+		c[i] = d.stack[base+off+i*step]
+	}
+	return c
+}
+
+// peek 返回下一个结构字符，索引用尽时返回 0
+func (d *indexParse) peek() byte {
+	if d.pos >= len(d.indices) {
+		return 0
+	}
+	return d.data[d.indices[d.pos]]
+}
+
+func (d *indexParse) indexValue() (*jsonValue, error) {
+	if d.pos >= len(d.indices) {
+		return nil, d.error(0, "number syntax invalid")
+	}
+	d.off = int(d.indices[d.pos])
+	d.pos++
+	v := d.newValue()
+	start := d.off
+	var err error
+	switch d.pop() {
+	case '[', '{':
+		if d.pop() == '[' {
+			err = d.indexArray(v)
+		} else {
+			err = d.indexObject(v)
+		}
+		if err == nil {
+			// 结束的括号是刚处理过的索引
+			v.start, v.end = start, int(d.indices[d.pos-1])+1
+			v.text = d.data[v.start:v.end]
+		}
+		return v, err
+	case '"':
+		err = d.indexString(v)
+	case 'n':
+		err = d.parseLiteral([]byte("null"), v, ValueNull)
+	case 't':
+		err = d.parseLiteral([]byte("true"), v, ValueTrue)
+	case 'f':
+		err = d.parseLiteral([]byte("false"), v, ValueFalse)
+	default:
+		err = d.parseNumber(v)
+	}
+	if err != nil {
+		return v, err
+	}
+	v.start, v.end = start, d.off
+	v.text = d.data[start:d.off]
+	// 标量之后只能是空白或结构字符
+	d.skipWhiteSpace()
+	if d.pos < len(d.indices) && d.off != int(d.indices[d.pos]) || d.pos == len(d.indices) && d.off != len(d.data) {
+		return v, &SyntaxError{msg: "unexpected end of JSON input", Offset: d.off}
+	}
+	return v, nil
+}
+
+// indexString 在没有转义时直接把内容复制到 strs 中，否则退回 parseString
+func (d *indexParse) indexString(v *jsonValue) error {
+	start := d.off + 1
+	end := bytes.IndexByte(d.data[start:], '"')
+	if end < 0 {
+		return d.error(0, "miss quotation mark")
+	}
+	s := d.data[start : start+end]
+	for _, c := range s {
+		if c == '\\' || c < 0x20 {
+			return d.parseString(v)
+		}
+	}
+	n := len(d.strs)
+	d.strs = append(d.strs, s...)
+	v.s = d.strs[n:len(d.strs):len(d.strs)]
+	v.valueType = ValueString
+	d.off = start + end + 1
+	return nil
+}
+
+func (d *indexParse) indexArray(v *jsonValue) error {
+	v.valueType = ValueArray
+	if d.peek() == ']' {
+		d.pos++
+		return nil
+	}
+	base := len(d.stack)
+	defer func() { d.stack = d.stack[:base] }()
+	for {
+		v2, err := d.indexValue()
+		if err != nil {
+			return err
+		}
+		d.stack = append(d.stack, v2)
+		c := d.peek()
+		d.pos++
+		if c == ']' {
+			v.array.values = d.children(base, 0, 1)
+			v.array.len = len(v.array.values)
+			return nil
+		}
+		if c != ',' {
+			return d.error(c, "MISS_COMMA_OR_SQUARE_BRACKET")
+		}
+	}
+}
+
+func (d *indexParse) indexObject(v *jsonValue) error {
+	v.valueType = ValueObject
+	if d.peek() == '}' {
+		d.pos++
+		return nil
+	}
+	base := len(d.stack)
+	defer func() { d.stack = d.stack[:base] }()
+	for {
+		c := d.peek()
+		if c != '"' {
+			return d.error(c, "miss key")
+		}
+		d.off = int(d.indices[d.pos])
+		d.pos++
+		key := d.newValue()
+		key.start = d.off
+		if err := d.indexString(key); err != nil {
+			return d.error(c, "miss key")
+		}
+		key.end = d.off
+		if c = d.peek(); c != ':' {
+			return d.error(c, "miss colon")
+		}
+		d.pos++
+		v2, err := d.indexValue()
+		if err != nil {
+			return err
+		}
+		d.stack = append(d.stack, key, v2)
+		c = d.peek()
+		d.pos++
+		if c == '}' {
+			v.object.keys = d.children(base, 0, 2)
+			v.object.values = d.children(base, 1, 2)
+			v.object.size = len(v.object.values)
+			return nil
+		}
+		if c != ',' {
+			return d.error(c, "miss comma or curly bracket")
+		}
+	}
+}
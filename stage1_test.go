@@ -0,0 +1,186 @@
+package json
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testIndexed(t *testing.T, source string) {
+	t.Helper()
+	expect, err := parseJson(t, []byte(source))
+	if err != nil {
+		return
+	}
+	v, err := parseIndexed([]byte(source))
+	if err != nil {
+		t.Errorf("parseIndexed %s error %s", source, err.Error())
+		return
+	}
+	if !reflect.DeepEqual(expect, v) {
+		t.Errorf("parseIndexed %s differs from parser", source)
+	}
+}
+
+func testIndexedError(t *testing.T, data []byte, msg string) {
+	t.Helper()
+	_, err := parseIndexed(data)
+	if err == nil {
+		t.Errorf("data %s should be error, but pass", data)
+		return
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Errorf("Data %s Should be error is [%s], but error is [%s]", data, msg, err.Error())
+	}
+}
+
+func TestSwarEqual(t *testing.T) {
+	x := uint64(0)
+	for i, c := range []byte(`a"b"\"{,`) {
+		x |= uint64(c) << (8 * i)
+	}
+	assertEqual(t, uint64(0b00101010), swarEqual(x, '"'))
+	assertEqual(t, uint64(0b00010000), swarEqual(x, '\\'))
+	assertEqual(t, uint64(0b01000000), swarEqual(x, '{'))
+	assertEqual(t, uint64(0), swarEqual(x, 'z'))
+}
+
+func TestBuildIndex(t *testing.T) {
+	indices, err := buildIndex([]byte(` {"a\"]" : [12, true,"x"]} `))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, []uint32{1, 2, 9, 11, 12, 14, 16, 20, 21, 24, 25}, indices)
+
+	_, err = buildIndex([]byte(`["abc`))
+	assertTrue(t, err != nil)
+}
+
+func TestParseIndexed(t *testing.T) {
+	testIndexed(t, "null")
+	testIndexed(t, " true ")
+	testIndexed(t, "-1.5e10")
+	testIndexed(t, `"Hello\nWorld 𝄞"`)
+	testIndexed(t, "[ [ ] , [ 0 ] , [ 0 , 1 ] , [ 0 , 1 , 2 ] ]")
+	testIndexed(t, `{"n":null,"f":false,"t":true,"i":123,"s":"abc","a":[1,2,3],"o":{"1":1,"2":2}}`)
+
+	// 字符串和转义跨越 64 字节块的边界
+	long := strings.Repeat("x", 60)
+	testIndexed(t, `["`+long+`\\", "`+long+`\"]{", "`+strings.Repeat(`\\`, 40)+`"]`)
+	testIndexed(t, `{"`+strings.Repeat("k", 70)+`": [`+strings.Repeat("1, ", 40)+`2]}`)
+}
+
+func TestParseIndexedInvalid(t *testing.T) {
+	testIndexedError(t, []byte(""), "number syntax invalid")
+	testIndexedError(t, []byte("nul"), "parseJson type 0 error")
+	testIndexedError(t, []byte("nullx"), "unexpected end of JSON input")
+	testIndexedError(t, []byte("null x"), "unexpected end of JSON input")
+	testIndexedError(t, []byte("[1,]"), "invalid character")
+	testIndexedError(t, []byte("[1 2"), "MISS_COMMA_OR_SQUARE_BRACKET")
+	testIndexedError(t, []byte("[1"), "MISS_COMMA_OR_SQUARE_BRACKET")
+	testIndexedError(t, []byte("{1:1}"), "miss key")
+	testIndexedError(t, []byte("{\"a\"}"), "miss colon")
+	testIndexedError(t, []byte("{\"a\": 1]"), "miss comma or curly bracket")
+	testIndexedError(t, []byte("\"abc"), "miss quotation mark")
+	testIndexedError(t, []byte("\"\x01\""), "invalid string char")
+
+	// 只有分隔符或结束括号时没有任何值
+	for _, s := range []string{"]", "}", ",", ":", " ] ", "],}"} {
+		testIndexedError(t, []byte(s), "invalid character")
+		_, err := Parse([]byte(s))
+		assertTrue(t, err != nil)
+	}
+	// 输入末尾的 NUL 字节不是输入结束
+	testIndexedError(t, []byte("0\x00"), "unexpected end of JSON input")
+	testError(t, []byte("0\x00"), "unexpected end of JSON input")
+}
+
+// FuzzParse 对比两阶段解析和逐字节解析的结果
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{"null", " ] ", `{"a": [1, "b\n", true]}`, "[1,]", `{"a" 1}`, "1e400", `"\ud800"`, "0\x00"} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		d := new(jsonParse)
+		d.init(data)
+		expect, err := d.parser()
+		v, err2 := parseIndexed(data)
+		if (err == nil) != (err2 == nil) {
+			t.Fatalf("parseIndexed %q error %v, parser error %v", data, err2, err)
+		}
+		if err == nil && !reflect.DeepEqual(expect, v) {
+			t.Fatalf("parseIndexed %q differs from parser", data)
+		}
+	})
+}
+
+// benchData 生成约 1MB 的测试数据
+func benchData() []byte {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < 5000; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"id": %d, "name": "user %d", "active": %t, "score": %d.%d, "tags": ["a", "b\n", "c"], "profile": {"city": "Shanghai", "zip": null}}`,
+			i, i, i%2 == 0, i*7, i%10)
+	}
+	b.WriteString("]")
+	return []byte(b.String())
+}
+
+func BenchmarkParse(b *testing.B) {
+	data := benchData()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if _, err := Parse(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParseBytewise 是不使用结构索引、逐字节解析的对照
+func BenchmarkParseBytewise(b *testing.B) {
+	data := benchData()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		d := new(jsonParse)
+		d.init(data)
+		if _, err := d.parser(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseIndexed(b *testing.B) {
+	data := benchData()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if _, err := parseIndexed(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBuildIndex(b *testing.B) {
+	data := benchData()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if _, err := buildIndex(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStdUnmarshal(b *testing.B) {
+	data := benchData()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		var v interface{}
+		if err := stdjson.Unmarshal(data, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
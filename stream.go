@@ -0,0 +1,420 @@
+package json
+
+import "io"
+
+// A Token holds a value of one of these types:
+//
+//	Delim, for the four JSON delimiters [ ] { }
+//	bool, for JSON booleans
+//	float64, for JSON numbers
+//	Number, for JSON numbers (after UseNumber)
+//	string, for JSON string literals
+//	nil, for JSON null
+type Token interface{}
+
+// A Delim is a JSON array or object delimiter, one of [ ] { or }.
+type Delim rune
+
+func (d Delim) String() string {
+	return string(d)
+}
+
+// Token 的读取状态，与 parseArray/parseObject 中的分隔符校验一一对应
+const (
+	tokenTopValue = iota
+	tokenArrayStart
+	tokenArrayValue
+	tokenArrayComma
+	tokenObjectStart
+	tokenObjectKey
+	tokenObjectColon
+	tokenObjectValue
+	tokenObjectComma
+)
+
+// A Decoder reads JSON tokens from an input stream or a byte slice.
+type Decoder struct {
+	r          io.Reader
+	d          jsonParse // d.data 是当前缓冲区，d.off 是读取位置
+	eof        bool
+	err        error
+	useNumber  bool
+	orderedMap bool
+	disallow   bool
+	tokenState int
+	tokenStack []int
+	scratch    jsonValue
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// NewBytesDecoder returns a new decoder that reads from data.
+func NewBytesDecoder(data []byte) *Decoder {
+	dec := &Decoder{eof: true}
+	dec.d.init(data)
+	return dec
+}
+
+// UseNumber causes the Decoder to return numbers as Number instead of
+// float64, both from Token and when Decode stores into an interface value.
+func (dec *Decoder) UseNumber() { dec.useNumber = true }
+
+// UseOrderedMap causes Decode to store JSON objects as *OrderedMap
+// instead of map[string]interface{} when decoding into an interface value.
+func (dec *Decoder) UseOrderedMap() { dec.orderedMap = true }
+
+// DisallowUnknownFields causes Decode to report object members that
+// do not match any exported field of the destination struct. All such
+// members are reported together, as FieldErrors.
+func (dec *Decoder) DisallowUnknownFields() { dec.disallow = true }
+
+// Decode reads the next JSON value from its input and stores it in
+// the value pointed to by v, following the rules of Unmarshal. It can
+// be mixed with Token, for example to decode the elements of a large
+// array one at a time.
+func (dec *Decoder) Decode(v interface{}) error {
+	if _, err := dec.peek(); err != nil {
+		if err == io.EOF && len(dec.tokenStack) > 0 {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if err := dec.tokenPrepareForDecode(); err != nil {
+		return err
+	}
+	if err := dec.tokenPrepareForValue(); err != nil {
+		return err
+	}
+	raw, err := dec.readValue()
+	if err != nil {
+		return err
+	}
+	dec.tokenValueEnd()
+	jv, err := Parse(raw)
+	if err != nil {
+		return err
+	}
+	d := &decodeState{useNumber: dec.useNumber, orderedMap: dec.orderedMap, disallowUnknown: dec.disallow}
+	return d.unmarshal(jv, v)
+}
+
+// tokenPrepareForDecode 在 Decode 前消费数组中的逗号或对象中的冒号
+func (dec *Decoder) tokenPrepareForDecode() error {
+	switch dec.tokenState {
+	case tokenArrayComma:
+		c, err := dec.peek()
+		if err != nil {
+			return err
+		}
+		if c != ',' {
+			return dec.d.error(c, "MISS_COMMA_OR_SQUARE_BRACKET")
+		}
+		dec.d.off++
+		dec.tokenState = tokenArrayValue
+	case tokenObjectColon:
+		c, err := dec.peek()
+		if err != nil {
+			return err
+		}
+		if c != ':' {
+			return dec.d.error(c, "miss colon")
+		}
+		dec.d.off++
+		dec.tokenState = tokenObjectValue
+	}
+	// 跳过分隔符之后的空白
+	if _, err := dec.peek(); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return nil
+}
+
+// readValue 保证当前位置的一个完整值已经读入缓冲区，返回它的副本，
+// 避免解析结果引用之后会被 fill 覆盖的缓冲区
+func (dec *Decoder) readValue() ([]byte, error) {
+	for {
+		start := dec.d.off
+		err := dec.d.skipValue()
+		// 值可能被缓冲区末尾截断，数字和字面量即使扫描成功也可能还没结束
+		// 出错的位置还在缓冲区内时，已读入的内容足以判定输入有误，不必再补充
+		incomplete := err == nil && dec.d.off == len(dec.d.data) || err != nil && dec.d.off >= len(dec.d.data)
+		if incomplete && !dec.eof {
+			dec.d.off = start
+			dec.fill()
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		raw := append([]byte(nil), dec.d.data[start:dec.d.off]...)
+		if c := raw[0]; c != '[' && c != '{' {
+			if err := dec.scalarEnd(); err != nil {
+				return nil, err
+			}
+		}
+		return raw, nil
+	}
+}
+
+// scalarEnd 检查标量之后是空白、结构字符或输入结束，拒绝 1true、truex 这样的输入
+func (dec *Decoder) scalarEnd() error {
+	if dec.d.off == len(dec.d.data) {
+		dec.fill()
+	}
+	d := &dec.d
+	if c := d.pop(); d.off < len(d.data) && (c == '"' || !isDelimiter(c)) {
+		return d.error(c, "after value")
+	}
+	return nil
+}
+
+// fill 从 r 读取更多数据，丢弃已经消费的部分
+func (dec *Decoder) fill() bool {
+	if dec.eof {
+		return false
+	}
+	buf := dec.d.data
+	if dec.d.off > 0 {
+		n := copy(buf, buf[dec.d.off:])
+		buf = buf[:n]
+		dec.d.off = 0
+	}
+	if cap(buf)-len(buf) < 512 {
+		nb := make([]byte, len(buf), 2*cap(buf)+512)
+		copy(nb, buf)
+		buf = nb
+	}
+	for {
+		n, err := dec.r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if err != nil {
+			dec.eof = true
+			if err != io.EOF {
+				dec.err = err
+			}
+		}
+		if n > 0 || dec.eof {
+			dec.d.data = buf
+			return n > 0
+		}
+	}
+}
+
+// peek 跳过空白并返回下一个字节，输入结束时返回 io.EOF
+func (dec *Decoder) peek() (byte, error) {
+	for {
+		dec.d.skipWhiteSpace()
+		if dec.d.off < len(dec.d.data) {
+			return dec.d.data[dec.d.off], nil
+		}
+		if !dec.fill() {
+			if dec.err != nil {
+				return 0, dec.err
+			}
+			return 0, io.EOF
+		}
+	}
+}
+
+// complete 保证从当前位置开始的字符串或标量已经完整读入缓冲区
+func (dec *Decoder) complete() {
+	for {
+		data, i := dec.d.data, dec.d.off
+		if data[i] == '"' {
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+		} else {
+			for ; i < len(data) && !isDelimiter(data[i]); i++ {
+			}
+		}
+		if i < len(data) || !dec.fill() {
+			return
+		}
+	}
+}
+
+// isDelimiter 判断 c 是否会结束一个数字或字面量
+func isDelimiter(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', ',', ':', '[', ']', '{', '}', '"':
+		return true
+	}
+	return false
+}
+
+// More reports whether there is another element in the
+// current array or object being parsed.
+func (dec *Decoder) More() bool {
+	c, err := dec.peek()
+	return err == nil && c != ']' && c != '}'
+}
+
+func (dec *Decoder) tokenPrepareForValue() error {
+	switch dec.tokenState {
+	case tokenArrayComma:
+		return dec.d.error(dec.d.pop(), "MISS_COMMA_OR_SQUARE_BRACKET")
+	case tokenObjectStart, tokenObjectKey:
+		return dec.d.error(dec.d.pop(), "miss key")
+	case tokenObjectColon:
+		return dec.d.error(dec.d.pop(), "miss colon")
+	case tokenObjectComma:
+		return dec.d.error(dec.d.pop(), "miss comma or curly bracket")
+	}
+	return nil
+}
+
+func (dec *Decoder) tokenValueEnd() {
+	switch dec.tokenState {
+	case tokenArrayStart, tokenArrayValue:
+		dec.tokenState = tokenArrayComma
+	case tokenObjectValue:
+		dec.tokenState = tokenObjectComma
+	}
+}
+
+func (dec *Decoder) pushState(state int) {
+	dec.tokenStack = append(dec.tokenStack, dec.tokenState)
+	dec.tokenState = state
+}
+
+func (dec *Decoder) popState() {
+	dec.tokenState = dec.tokenStack[len(dec.tokenStack)-1]
+	dec.tokenStack = dec.tokenStack[:len(dec.tokenStack)-1]
+	dec.tokenValueEnd()
+}
+
+// Token returns the next JSON token in the input stream.
+// At the end of the input stream, Token returns nil, io.EOF.
+func (dec *Decoder) Token() (Token, error) {
+	for {
+		c, err := dec.peek()
+		if err != nil {
+			if err == io.EOF && len(dec.tokenStack) > 0 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		switch c {
+		case '[':
+			if err := dec.tokenPrepareForValue(); err != nil {
+				return nil, err
+			}
+			dec.d.off++
+			dec.pushState(tokenArrayStart)
+			return Delim('['), nil
+		case ']':
+			if dec.tokenState != tokenArrayStart && dec.tokenState != tokenArrayComma {
+				return nil, dec.tokenUnexpected(c)
+			}
+			dec.d.off++
+			dec.popState()
+			return Delim(']'), nil
+		case '{':
+			if err := dec.tokenPrepareForValue(); err != nil {
+				return nil, err
+			}
+			dec.d.off++
+			dec.pushState(tokenObjectStart)
+			return Delim('{'), nil
+		case '}':
+			if dec.tokenState != tokenObjectStart && dec.tokenState != tokenObjectComma {
+				return nil, dec.tokenUnexpected(c)
+			}
+			dec.d.off++
+			dec.popState()
+			return Delim('}'), nil
+		case ':':
+			if dec.tokenState != tokenObjectColon {
+				return nil, dec.tokenUnexpected(c)
+			}
+			dec.d.off++
+			dec.tokenState = tokenObjectValue
+		case ',':
+			if dec.tokenState == tokenArrayComma {
+				dec.d.off++
+				dec.tokenState = tokenArrayValue
+			} else if dec.tokenState == tokenObjectComma {
+				dec.d.off++
+				dec.tokenState = tokenObjectKey
+			} else {
+				return nil, dec.tokenUnexpected(c)
+			}
+		case '"':
+			if dec.tokenState == tokenObjectStart || dec.tokenState == tokenObjectKey {
+				dec.complete()
+				if err := dec.d.parseString(&dec.scratch); err != nil {
+					return nil, err
+				}
+				dec.tokenState = tokenObjectColon
+				return string(dec.scratch.s), nil
+			}
+			fallthrough
+		default:
+			if err := dec.tokenPrepareForValue(); err != nil {
+				return nil, err
+			}
+			tok, err := dec.scalar()
+			if err != nil {
+				return nil, err
+			}
+			if err := dec.scalarEnd(); err != nil {
+				return nil, err
+			}
+			dec.tokenValueEnd()
+			return tok, nil
+		}
+	}
+}
+
+// tokenUnexpected 在出现不合法的分隔符时给出与 parseArray/parseObject 一致的错误
+func (dec *Decoder) tokenUnexpected(c byte) error {
+	switch dec.tokenState {
+	case tokenArrayStart, tokenArrayValue, tokenObjectValue, tokenTopValue:
+		return dec.d.error(c, "number syntax invalid")
+	case tokenArrayComma:
+		return dec.d.error(c, "MISS_COMMA_OR_SQUARE_BRACKET")
+	case tokenObjectStart, tokenObjectKey:
+		return dec.d.error(c, "miss key")
+	case tokenObjectColon:
+		return dec.d.error(c, "miss colon")
+	default:
+		return dec.d.error(c, "miss comma or curly bracket")
+	}
+}
+
+// scalar 复用 parseLiteral/parseString/parseNumber 读取一个标量
+func (dec *Decoder) scalar() (Token, error) {
+	dec.complete()
+	d, v := &dec.d, &dec.scratch
+	switch d.pop() {
+	case 'n':
+		return nil, d.parseLiteral([]byte("null"), v, ValueNull)
+	case 't':
+		return true, d.parseLiteral([]byte("true"), v, ValueTrue)
+	case 'f':
+		return false, d.parseLiteral([]byte("false"), v, ValueFalse)
+	case '"':
+		if err := d.parseString(v); err != nil {
+			return nil, err
+		}
+		return string(v.s), nil
+	default:
+		start := d.off
+		if err := d.parseNumber(v); err != nil {
+			return nil, err
+		}
+		if dec.useNumber {
+			return Number(d.data[start:d.off]), nil
+		}
+		return v.n, nil
+	}
+}
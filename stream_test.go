@@ -0,0 +1,215 @@
+package json
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func readTokens(t *testing.T, dec *Decoder) ([]Token, error) {
+	t.Helper()
+	var tokens []Token
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return tokens, nil
+		}
+		if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, tok)
+	}
+}
+
+func testTokens(t *testing.T, source string, expect []Token) {
+	t.Helper()
+	tokens, err := readTokens(t, NewBytesDecoder([]byte(source)))
+	if err != nil {
+		t.Errorf("Token %s error %s", source, err.Error())
+	}
+	assertEqual(t, expect, tokens)
+
+	// 逐字节读取，验证缓冲区补充逻辑
+	tokens, err = readTokens(t, NewDecoder(iotest.OneByteReader(strings.NewReader(source))))
+	if err != nil {
+		t.Errorf("Token %s error %s", source, err.Error())
+	}
+	assertEqual(t, expect, tokens)
+}
+
+func testTokenError(t *testing.T, source string, msg string) {
+	t.Helper()
+	_, err := readTokens(t, NewDecoder(strings.NewReader(source)))
+	if err == nil {
+		t.Errorf("data %s should be error, but pass", source)
+		return
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Errorf("Data %s Should be error is [%s], but error is [%s]", source, msg, err.Error())
+	}
+}
+
+func TestToken(t *testing.T) {
+	testTokens(t, `null true false 1.5 "abc"`, []Token{nil, true, false, 1.5, "abc"})
+	testTokens(t, `[1, [], {}, "x\ny"]`, []Token{
+		Delim('['), 1.0, Delim('['), Delim(']'), Delim('{'), Delim('}'), "x\ny", Delim(']'),
+	})
+	testTokens(t, ` { "a" : [ true , null ] , "b" : { "c" : -12e3 } } `, []Token{
+		Delim('{'), "a", Delim('['), true, nil, Delim(']'), "b", Delim('{'), "c", -12e3, Delim('}'), Delim('}'),
+	})
+}
+
+func TestTokenUseNumber(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`[12345678901234567890, 1.50]`))
+	dec.UseNumber()
+	tokens, err := readTokens(t, dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, []Token{Delim('['), Number("12345678901234567890"), Number("1.50"), Delim(']')}, tokens)
+}
+
+func TestTokenMore(t *testing.T) {
+	dec := NewDecoder(iotest.OneByteReader(strings.NewReader(`[{"id": 1}, {"id": 2}, {"id": 3}]`)))
+	if _, err := dec.Token(); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for dec.More() {
+		tokens := make([]Token, 0, 4)
+		for i := 0; i < 4; i++ {
+			tok, err := dec.Token()
+			if err != nil {
+				t.Fatal(err)
+			}
+			tokens = append(tokens, tok)
+		}
+		count++
+		assertEqual(t, []Token{Delim('{'), "id", float64(count), Delim('}')}, tokens)
+	}
+	tok, err := dec.Token()
+	assertTrue(t, err == nil && tok == Delim(']'))
+	assertEqual(t, 3, count)
+}
+
+func TestTokenInvalid(t *testing.T) {
+	testTokenError(t, "[1 2]", "MISS_COMMA_OR_SQUARE_BRACKET")
+	testTokenError(t, "[1,]", "invalid character")
+	testTokenError(t, "[1}", "MISS_COMMA_OR_SQUARE_BRACKET")
+	testTokenError(t, `{1:1}`, "miss key")
+	testTokenError(t, `{"a" 1}`, "miss colon")
+	testTokenError(t, `{"a":1 "b":2}`, "miss comma or curly bracket")
+	testTokenError(t, `{"a":1]`, "miss comma or curly bracket")
+	testTokenError(t, `nul`, "parseJson type 0 error")
+	testTokenError(t, `"abc`, "miss quotation mark")
+	testTokenError(t, `[1,`, "unexpected EOF")
+
+	// 标量之后必须是空白、结构字符或输入结束
+	testTokenError(t, `1true`, "invalid character t after value")
+	testTokenError(t, `truex`, "invalid character x after value")
+	testTokenError(t, `[null"a"]`, "invalid character \" after value")
+	testTokenError(t, `{"a":"b"1}`, "invalid character 1 after value")
+	testTokens(t, `1 true[]"a"{}`, []Token{1.0, true, Delim('['), Delim(']'), "a", Delim('{'), Delim('}')})
+}
+
+func TestDecoderDecode(t *testing.T) {
+	source := ` {"a":[1,2.5,"x"]} 12345 [true,null] "s" `
+	for _, dec := range []*Decoder{
+		NewBytesDecoder([]byte(source)),
+		NewDecoder(iotest.OneByteReader(strings.NewReader(source))),
+	} {
+		var values []interface{}
+		for {
+			var v interface{}
+			err := dec.Decode(&v)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			values = append(values, v)
+		}
+		assertEqual(t, []interface{}{
+			map[string]interface{}{"a": []interface{}{1.0, 2.5, "x"}},
+			12345.0,
+			[]interface{}{true, nil},
+			"s",
+		}, values)
+	}
+}
+
+func TestDecoderDecodeTokens(t *testing.T) {
+	// 用 Token 进入数组，再逐个 Decode 元素
+	dec := NewDecoder(iotest.OneByteReader(strings.NewReader(`{"items":[{"n":1},{"n":2}]}`)))
+	type item struct{ N int }
+	var items []item
+	for _, want := range []Token{Delim('{'), "items", Delim('[')} {
+		tok, err := dec.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, want, tok)
+	}
+	for dec.More() {
+		var it item
+		if err := dec.Decode(&it); err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, it)
+	}
+	assertEqual(t, []item{{1}, {2}}, items)
+	tokens, err := readTokens(t, dec)
+	assertTrue(t, err == nil)
+	assertEqual(t, []Token{Delim(']'), Delim('}')}, tokens)
+}
+
+func TestDecoderDecodeError(t *testing.T) {
+	var v interface{}
+	dec := NewDecoder(strings.NewReader(`[1,2`))
+	assertEqual(t, "invalid character \x00 MISS_COMMA_OR_SQUARE_BRACKET", dec.Decode(&v).Error())
+
+	dec = NewBytesDecoder([]byte(`{"a" 1}`))
+	dec.Token()
+	assertEqual(t, "invalid character \" miss key", dec.Decode(&v).Error())
+
+	dec = NewBytesDecoder([]byte(`12true`))
+	assertEqual(t, "invalid character t after value", dec.Decode(&v).Error())
+
+	// 已读入的内容能判定错误时立即返回，不再读取后面的数据
+	dec = NewDecoder(io.MultiReader(strings.NewReader(`[1 2`), iotest.ErrReader(errors.New("read too far"))))
+	assertEqual(t, "invalid character 2 MISS_COMMA_OR_SQUARE_BRACKET", dec.Decode(&v).Error())
+}
+
+func TestDecoderUseNumber(t *testing.T) {
+	dec := NewBytesDecoder([]byte(`{"big":12345678901234567890,"f":1.50,"a":[-0]}`))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, map[string]interface{}{
+		"big": Number("12345678901234567890"),
+		"f":   Number("1.50"),
+		"a":   []interface{}{Number("-0")},
+	}, v)
+}
+
+func TestDecoderUseOrderedMap(t *testing.T) {
+	dec := NewBytesDecoder([]byte(`{"z":1,"a":{"y":true,"b":null},"m":[{"k":"v"}]}`))
+	dec.UseOrderedMap()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	m, ok := v.(*OrderedMap)
+	assertTrue(t, ok)
+	assertEqual(t, []string{"z", "a", "m"}, m.Keys())
+	a, _ := m.Get("a")
+	assertEqual(t, []string{"y", "b"}, a.(*OrderedMap).Keys())
+	b, err := Marshal(v)
+	assertTrue(t, err == nil)
+	assertEqual(t, `{"z":1,"a":{"y":true,"b":null},"m":[{"k":"v"}]}`, string(b))
+}
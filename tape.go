@@ -0,0 +1,503 @@
+package json
+
+import (
+	"encoding/binary"
+	"iter"
+	"math"
+	"strconv"
+)
+
+// A Tape is a parsed JSON document stored as a flat sequence of tagged
+// 64-bit words, with string contents in a side buffer. It uses much
+// less memory than a Value tree and is read through TapeValue.
+//
+// 每个节点编码为若干个 64 位字，高 8 位是类型标记，低 56 位是负载；
+// 字符串内容统一存放在 strings 中（4 字节长度 + 内容）。
+//
+//	null/true/false : 单个字，负载为 0
+//	number          : 标记字 + 一个保存 float64 位模式的字
+//	string          : 负载为 strings 中的偏移
+//	[ {             : 负载为 元素个数<<32 | 结束字之后的下标，个数超过 24 位时饱和
+//	] }             : 负载为对应开始字的下标
+type Tape struct {
+	words   []uint64
+	strings []byte
+}
+
+const (
+	tapeTagShift   = 56
+	tapePayload    = 1<<tapeTagShift - 1
+	tapeCountShift = 32
+	tapeIndexMask  = 1<<tapeCountShift - 1
+	tapeCountMax   = tapePayload >> tapeCountShift
+)
+
+const (
+	tapeRoot        byte = 'r'
+	tapeNull        byte = 'n'
+	tapeTrue        byte = 't'
+	tapeFalse       byte = 'f'
+	tapeNumber      byte = 'd'
+	tapeString      byte = '"'
+	tapeStartArray  byte = '['
+	tapeEndArray    byte = ']'
+	tapeStartObject byte = '{'
+	tapeEndObject   byte = '}'
+)
+
+func (t *Tape) append(tag byte, payload uint64) int {
+	t.words = append(t.words, uint64(tag)<<tapeTagShift|payload&tapePayload)
+	return len(t.words) - 1
+}
+
+func (t *Tape) set(index int, tag byte, payload uint64) {
+	t.words[index] = uint64(tag)<<tapeTagShift | payload&tapePayload
+}
+
+func (t *Tape) tag(index int) byte {
+	return byte(t.words[index] >> tapeTagShift)
+}
+
+func (t *Tape) payload(index int) uint64 {
+	return t.words[index] & tapePayload
+}
+
+func (t *Tape) appendString(s []byte) {
+	off := len(t.strings)
+	var l [4]byte
+	binary.LittleEndian.PutUint32(l[:], uint32(len(s)))
+	t.strings = append(t.strings, l[:]...)
+	t.strings = append(t.strings, s...)
+	t.append(tapeString, uint64(off))
+}
+
+// after 返回 index 处节点之后的下一个节点下标
+func (t *Tape) after(index int) int {
+	switch t.tag(index) {
+	case tapeNumber:
+		return index + 2
+	case tapeStartArray, tapeStartObject:
+		return int(t.payload(index) & tapeIndexMask)
+	default:
+		return index + 1
+	}
+}
+
+// tapeParse 复用 jsonParse 的扫描逻辑，把结果直接写入 tape，不生成 jsonValue 树
+type tapeParse struct {
+	jsonParse
+	tape    *Tape
+	scratch jsonValue
+}
+
+// ParseTape parses the JSON-encoded data into a Tape.
+func ParseTape(data []byte) (*Tape, error) {
+	d := &tapeParse{tape: &Tape{}}
+	d.init(data)
+	if err := d.parserTape(); err != nil {
+		return nil, err
+	}
+	return d.tape, nil
+}
+
+func (d *tapeParse) parserTape() error {
+	root := d.tape.append(tapeRoot, 0)
+	d.skipWhiteSpace()
+	if err := d.tapeValue(); err != nil {
+		return err
+	}
+	d.skipWhiteSpace()
+	if d.off < len(d.data) {
+		return &SyntaxError{msg: "unexpected end of JSON input", Offset: d.off}
+	}
+	end := d.tape.append(tapeRoot, uint64(root))
+	d.tape.set(root, tapeRoot, uint64(end+1))
+	return nil
+}
+
+func (d *tapeParse) tapeValue() error {
+	v := &d.scratch
+	switch d.pop() {
+	case 'n':
+		if err := d.parseLiteral([]byte("null"), v, ValueNull); err != nil {
+			return err
+		}
+		d.tape.append(tapeNull, 0)
+	case 't':
+		if err := d.parseLiteral([]byte("true"), v, ValueTrue); err != nil {
+			return err
+		}
+		d.tape.append(tapeTrue, 0)
+	case 'f':
+		if err := d.parseLiteral([]byte("false"), v, ValueFalse); err != nil {
+			return err
+		}
+		d.tape.append(tapeFalse, 0)
+	case '"':
+		if err := d.parseString(v); err != nil {
+			return err
+		}
+		d.tape.appendString(v.s)
+	case '[':
+		return d.tapeArray()
+	case '{':
+		return d.tapeObject()
+	default:
+		if err := d.parseNumber(v); err != nil {
+			return err
+		}
+		d.tape.append(tapeNumber, 0)
+		d.tape.words = append(d.tape.words, math.Float64bits(v.n))
+	}
+	return nil
+}
+
+func (d *tapeParse) tapeArray() error {
+	start := d.tape.append(tapeStartArray, 0)
+	count := 0
+	d.next()
+	d.skipWhiteSpace()
+	if c := d.pop(); c != ']' {
+		for {
+			d.skipWhiteSpace()
+			if err := d.tapeValue(); err != nil {
+				return err
+			}
+			count++
+			d.skipWhiteSpace()
+			c = d.pop()
+			if c == ',' {
+				d.next()
+			} else if c == ']' {
+				break
+			} else {
+				return d.error(c, "MISS_COMMA_OR_SQUARE_BRACKET")
+			}
+		}
+	}
+	d.next()
+	end := d.tape.append(tapeEndArray, uint64(start))
+	d.tape.set(start, tapeStartArray, uint64(min(count, tapeCountMax))<<tapeCountShift|uint64(end+1))
+	return nil
+}
+
+func (d *tapeParse) tapeObject() error {
+	start := d.tape.append(tapeStartObject, 0)
+	count := 0
+	d.next()
+	d.skipWhiteSpace()
+	if c := d.pop(); c != '}' {
+		for {
+			// 解析key
+			d.skipWhiteSpace()
+			if err := d.parseString(&d.scratch); err != nil {
+				return d.error(c, "miss key")
+			}
+			d.tape.appendString(d.scratch.s)
+			// 解析 ：字符
+			d.skipWhiteSpace()
+			c = d.pop()
+			if c != ':' {
+				return d.error(c, "miss colon")
+			}
+			d.next()
+			// 解析value
+			d.skipWhiteSpace()
+			if err := d.tapeValue(); err != nil {
+				return err
+			}
+			count++
+			// 解析分隔符、结束符
+			d.skipWhiteSpace()
+			c = d.pop()
+			if c == ',' {
+				d.next()
+			} else if c == '}' {
+				break
+			} else {
+				return d.error(c, "miss comma or curly bracket")
+			}
+		}
+	}
+	d.next()
+	end := d.tape.append(tapeEndObject, uint64(start))
+	d.tape.set(start, tapeStartObject, uint64(min(count, tapeCountMax))<<tapeCountShift|uint64(end+1))
+	return nil
+}
+
+// A TapeValue is a node of a Tape. It is a small value that points
+// into the Tape and may be copied freely.
+type TapeValue struct {
+	t     *Tape
+	index int
+}
+
+// Root returns the top-level value of the document.
+func (t *Tape) Root() TapeValue {
+	return TapeValue{t: t, index: 1}
+}
+
+// Elements returns an iterator over the index and value of each array
+// element. It yields nothing if v is not an array. Each step skips the
+// previous element's subtree using its stored end offset.
+func (v TapeValue) Elements() iter.Seq2[int, TapeValue] {
+	return func(yield func(int, TapeValue) bool) {
+		if v.t.tag(v.index) != tapeStartArray {
+			return
+		}
+		end := v.end()
+		for i, n := v.index+1, 0; i < end; i, n = v.t.after(i), n+1 {
+			if !yield(n, TapeValue{t: v.t, index: i}) {
+				return
+			}
+		}
+	}
+}
+
+// Members returns an iterator over the key and value of each object
+// member, in document order. It yields nothing if v is not an object.
+func (v TapeValue) Members() iter.Seq2[string, TapeValue] {
+	return func(yield func(string, TapeValue) bool) {
+		if v.t.tag(v.index) != tapeStartObject {
+			return
+		}
+		end := v.end()
+		for i := v.index + 1; i < end; i = v.t.after(v.t.after(i)) {
+			k, _ := TapeValue{t: v.t, index: i}.getString()
+			if !yield(k, TapeValue{t: v.t, index: v.t.after(i)}) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over v and all of its descendants in
+// depth-first order, each paired with its JSON Pointer relative to v,
+// like Value.All.
+func (v TapeValue) All() iter.Seq2[string, TapeValue] {
+	return func(yield func(string, TapeValue) bool) {
+		v.all("", yield)
+	}
+}
+
+func (v TapeValue) all(path string, yield func(string, TapeValue) bool) bool {
+	if !yield(path, v) {
+		return false
+	}
+	for i, e := range v.Elements() {
+		if !e.all(path+"/"+strconv.Itoa(i), yield) {
+			return false
+		}
+	}
+	for k, e := range v.Members() {
+		if !e.all(path+"/"+escapePointer(k), yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalJSON returns the compact JSON encoding of v.
+func (v TapeValue) MarshalJSON() ([]byte, error) {
+	return v.append(nil)
+}
+
+// append 把 v 生成为紧凑的 JSON 文本追加到 dst
+func (v TapeValue) append(dst []byte) ([]byte, error) {
+	var err error
+	switch v.t.tag(v.index) {
+	case tapeTrue:
+		return append(dst, "true"...), nil
+	case tapeFalse:
+		return append(dst, "false"...), nil
+	case tapeNumber:
+		n, _ := v.getNumber()
+		return appendFloat(dst, n, 64)
+	case tapeString:
+		s, _ := v.getString()
+		return appendString(dst, s), nil
+	case tapeStartArray:
+		dst = append(dst, '[')
+		for i, e := range v.Elements() {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			if dst, err = e.append(dst); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, ']'), nil
+	case tapeStartObject:
+		dst = append(dst, '{')
+		first := true
+		for k, e := range v.Members() {
+			if !first {
+				dst = append(dst, ',')
+			}
+			first = false
+			dst = append(appendString(dst, k), ':')
+			if dst, err = e.append(dst); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, '}'), nil
+	}
+	return append(dst, "null"...), nil
+}
+
+// DecodeTapeValue decodes the tape node v into a new value of type T,
+// following the same rules as Unmarshal.
+func DecodeTapeValue[T any](v TapeValue) (T, error) {
+	return DecodeValue[T](v.value())
+}
+
+// value 把 v 及其子节点转成 jsonValue 树
+func (v TapeValue) value() *jsonValue {
+	jv := &jsonValue{valueType: v.getValueType()}
+	switch jv.valueType {
+	case ValueNumber:
+		jv.n, _ = v.getNumber()
+	case ValueString:
+		s, _ := v.getString()
+		jv.s = []byte(s)
+	case ValueArray:
+		for _, e := range v.Elements() {
+			jv.array.values = append(jv.array.values, e.value())
+		}
+		jv.array.len = len(jv.array.values)
+	case ValueObject:
+		for k, e := range v.Members() {
+			jv.object.keys = append(jv.object.keys, &jsonValue{valueType: ValueString, s: []byte(k)})
+			jv.object.values = append(jv.object.values, e.value())
+		}
+		jv.object.size = len(jv.object.values)
+	}
+	return jv
+}
+
+// end 返回数组或对象结束字的下标
+func (v TapeValue) end() int {
+	return int(v.t.payload(v.index)&tapeIndexMask) - 1
+}
+
+func (v TapeValue) getValueType() ValueType {
+	switch v.t.tag(v.index) {
+	case tapeTrue:
+		return ValueTrue
+	case tapeFalse:
+		return ValueFalse
+	case tapeNumber:
+		return ValueNumber
+	case tapeString:
+		return ValueString
+	case tapeStartArray:
+		return ValueArray
+	case tapeStartObject:
+		return ValueObject
+	default:
+		return ValueNull
+	}
+}
+
+func (v TapeValue) getBoolean() (bool, error) {
+	tag := v.t.tag(v.index)
+	if tag != tapeTrue && tag != tapeFalse {
+		return false, v.error("value type isn't boolean")
+	}
+	return tag == tapeTrue, nil
+}
+
+func (v TapeValue) getNumber() (float64, error) {
+	if v.t.tag(v.index) != tapeNumber {
+		return 0.0, v.error("value type isn't number")
+	}
+	return math.Float64frombits(v.t.words[v.index+1]), nil
+}
+
+func (v TapeValue) getString() (string, error) {
+	if v.t.tag(v.index) != tapeString {
+		return "", v.error("value type isn't string")
+	}
+	off := v.t.payload(v.index)
+	l := uint64(binary.LittleEndian.Uint32(v.t.strings[off:]))
+	return string(v.t.strings[off+4 : off+4+l]), nil
+}
+
+func (v TapeValue) count() int {
+	n := int(v.t.payload(v.index) >> tapeCountShift)
+	if n < tapeCountMax {
+		return n
+	}
+	// 计数已饱和，遍历得到实际个数
+	n = 0
+	for i := v.index + 1; i < v.end(); i = v.t.after(i) {
+		n++
+	}
+	if v.t.tag(v.index) == tapeStartObject {
+		n /= 2
+	}
+	return n
+}
+
+func (v TapeValue) getArrayLen() int {
+	if v.t.tag(v.index) != tapeStartArray {
+		return 0
+	}
+	return v.count()
+}
+
+func (v TapeValue) getArrayElem(index int) (TapeValue, error) {
+	if v.t.tag(v.index) != tapeStartArray {
+		return TapeValue{}, v.error("value type isn't array")
+	}
+	if index < 0 || index > v.count()-1 {
+		return TapeValue{}, v.error("array out range")
+	}
+	i := v.index + 1
+	for ; index > 0; index-- {
+		i = v.t.after(i)
+	}
+	return TapeValue{t: v.t, index: i}, nil
+}
+
+func (v TapeValue) getObjectSize() int {
+	if v.t.tag(v.index) != tapeStartObject {
+		return 0
+	}
+	return v.count()
+}
+
+// member 返回第 index 个成员 key 的下标
+func (v TapeValue) member(index int) (int, error) {
+	if v.t.tag(v.index) != tapeStartObject {
+		return 0, v.error("value type isn't object")
+	}
+	if index < 0 || index > v.count()-1 {
+		return 0, v.error("object out range")
+	}
+	i := v.index + 1
+	for ; index > 0; index-- {
+		i = v.t.after(v.t.after(i))
+	}
+	return i, nil
+}
+
+func (v TapeValue) getObjectKey(index int) (TapeValue, error) {
+	i, err := v.member(index)
+	if err != nil {
+		return TapeValue{}, err
+	}
+	return TapeValue{t: v.t, index: i}, nil
+}
+
+func (v TapeValue) getObjectValue(index int) (TapeValue, error) {
+	i, err := v.member(index)
+	if err != nil {
+		return TapeValue{}, err
+	}
+	return TapeValue{t: v.t, index: v.t.after(i)}, nil
+}
+
+func (v TapeValue) error(msg string) error {
+	return &jsonValueError{msg}
+}
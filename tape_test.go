@@ -0,0 +1,211 @@
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+func parseTapeJson(t *testing.T, data []byte) (TapeValue, bool) {
+	t.Helper()
+	tp, err := ParseTape(data)
+	if err != nil {
+		t.Errorf("ParseTape %s error %s", string(data), err.Error())
+		return TapeValue{}, false
+	}
+	return tp.Root(), true
+}
+
+func testTapeError(t *testing.T, data []byte, msg string) {
+	t.Helper()
+	_, err := ParseTape(data)
+	if err == nil {
+		t.Errorf("data %s should be error, but pass", data)
+		return
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Errorf("Data %s Should be error is [%s], but error is [%s]", data, msg, err.Error())
+	}
+}
+
+func TestTapeLiteral(t *testing.T) {
+	v, ok := parseTapeJson(t, []byte("null"))
+	if ok {
+		assertTrue(t, v.getValueType() == ValueNull)
+	}
+	v, ok = parseTapeJson(t, []byte(" true "))
+	if ok {
+		b, err := v.getBoolean()
+		assertTrue(t, err == nil && b)
+	}
+	v, ok = parseTapeJson(t, []byte("false"))
+	if ok {
+		b, err := v.getBoolean()
+		assertTrue(t, err == nil && !b)
+	}
+}
+
+func TestTapeNumberAndString(t *testing.T) {
+	v, ok := parseTapeJson(t, []byte("-1.5e3"))
+	if ok {
+		n, _ := v.getNumber()
+		assertEqual(t, -1.5e3, n)
+	}
+	v, ok = parseTapeJson(t, []byte("\"Hello\\nWorld \\uD834\\uDD1E\""))
+	if ok {
+		s, _ := v.getString()
+		assertEqual(t, "Hello\nWorld 𝄞", s)
+		_, err := v.getNumber()
+		assertTrue(t, err != nil)
+	}
+}
+
+func TestTapeArray(t *testing.T) {
+	v, ok := parseTapeJson(t, []byte("[ [ ] , [ 0 ] , [ 0 , 1 ] , [ 0 , 1 , 2 ] ]"))
+	if !ok {
+		return
+	}
+	assertTrue(t, v.getValueType() == ValueArray)
+	assertEqual(t, 4, v.getArrayLen())
+	for i := 0; i < 4; i++ {
+		e, err := v.getArrayElem(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertTrue(t, e.getValueType() == ValueArray)
+		assertEqual(t, i, e.getArrayLen())
+		for j := 0; j < i; j++ {
+			n, _ := e.getArrayElem(j)
+			f, _ := n.getNumber()
+			assertEqual(t, float64(j), f)
+		}
+	}
+	_, err := v.getArrayElem(4)
+	assertTrue(t, err != nil)
+}
+
+func TestTapeObject(t *testing.T) {
+	v, ok := parseTapeJson(t, []byte(` {
+	"n" : null ,
+	"f" : false ,
+	"t" : true ,
+	"i" : 123 ,
+	"s" : "abc",
+	"a" : [ 1, 2, 3 ],
+	"o" : { "1" : 1, "2" : 2, "3" : 3 }
+	 } `))
+	if !ok {
+		return
+	}
+	assertTrue(t, v.getValueType() == ValueObject)
+	assertEqual(t, 7, v.getObjectSize())
+
+	keys := []string{"n", "f", "t", "i", "s", "a", "o"}
+	for i, key := range keys {
+		k, _ := v.getObjectKey(i)
+		s, _ := k.getString()
+		assertEqual(t, key, s)
+	}
+	i, _ := v.getObjectValue(3)
+	n, _ := i.getNumber()
+	assertEqual(t, 123.0, n)
+
+	a, _ := v.getObjectValue(5)
+	assertEqual(t, 3, a.getArrayLen())
+	a2, _ := a.getArrayElem(2)
+	n, _ = a2.getNumber()
+	assertEqual(t, 3.0, n)
+
+	o, _ := v.getObjectValue(6)
+	assertEqual(t, 3, o.getObjectSize())
+	o3, _ := o.getObjectValue(2)
+	n, _ = o3.getNumber()
+	assertEqual(t, 3.0, n)
+}
+
+func TestTapeIter(t *testing.T) {
+	v, ok := parseTapeJson(t, []byte(`{"a": [1, "x", true], "b": {}}`))
+	if !ok {
+		return
+	}
+	var keys []string
+	for k, e := range v.Members() {
+		keys = append(keys, k)
+		if k != "a" {
+			assertEqual(t, ValueObject, e.getValueType())
+			continue
+		}
+		var got []interface{}
+		for i, e := range e.Elements() {
+			assertEqual(t, len(got), i)
+			x, err := DecodeTapeValue[interface{}](e)
+			assertTrue(t, err == nil)
+			got = append(got, x)
+		}
+		assertEqual(t, []interface{}{1.0, "x", true}, got)
+	}
+	assertEqual(t, []string{"a", "b"}, keys)
+	for range v.Elements() {
+		t.Error("object should yield no elements")
+	}
+	var paths []string
+	for p := range v.All() {
+		paths = append(paths, p)
+	}
+	assertEqual(t, []string{"", "/a", "/a/0", "/a/1", "/a/2", "/b"}, paths)
+}
+
+func TestTapeMarshal(t *testing.T) {
+	data := `{"a":[1.5,"x\n",true,null],"b":{},"c":[]}`
+	v, ok := parseTapeJson(t, []byte(data))
+	if !ok {
+		return
+	}
+	b, err := v.MarshalJSON()
+	assertTrue(t, err == nil)
+	assertEqual(t, data, string(b))
+	b, err = Marshal(map[string]TapeValue{"t": v})
+	assertTrue(t, err == nil)
+	assertEqual(t, `{"t":`+data+`}`, string(b))
+	type S struct {
+		A []interface{} `json:"a"`
+		C []int         `json:"c"`
+	}
+	s, err := DecodeTapeValue[S](v)
+	assertTrue(t, err == nil)
+	assertEqual(t, S{A: []interface{}{1.5, "x\n", true, nil}, C: []int{}}, s)
+}
+
+func TestTapeCountSaturated(t *testing.T) {
+	// 构造计数已饱和的节点，个数需要遍历得到
+	v, ok := parseTapeJson(t, []byte(`[[null, 1, [2], {"a": 3}, "b"], {"a": 1, "b": [2]}]`))
+	if !ok {
+		return
+	}
+	for _, i := range []int{0, 1} {
+		e, _ := v.getArrayElem(i)
+		e.t.set(e.index, e.t.tag(e.index), tapeCountMax<<tapeCountShift|e.t.payload(e.index)&tapeIndexMask)
+	}
+	a, _ := v.getArrayElem(0)
+	assertEqual(t, 5, a.getArrayLen())
+	e, err := a.getArrayElem(4)
+	assertTrue(t, err == nil)
+	s, _ := e.getString()
+	assertEqual(t, "b", s)
+	o, _ := v.getArrayElem(1)
+	assertEqual(t, 2, o.getObjectSize())
+	k, err := o.getObjectKey(1)
+	assertTrue(t, err == nil)
+	s, _ = k.getString()
+	assertEqual(t, "b", s)
+}
+
+func TestTapeInvalid(t *testing.T) {
+	testTapeError(t, []byte("nul"), "parseJson type 0 error")
+	testTapeError(t, []byte("null x"), "unexpected end of JSON input")
+	testTapeError(t, []byte("null\x00"), "unexpected end of JSON input")
+	testTapeError(t, []byte("[1 2"), "MISS_COMMA_OR_SQUARE_BRACKET")
+	testTapeError(t, []byte("{1:1,"), "miss key")
+	testTapeError(t, []byte("{\"a\"}"), "miss colon")
+	testTapeError(t, []byte("{\"a\": 1]"), "miss comma or curly bracket")
+	testTapeError(t, []byte("1e309"), "number out of range")
+}
@@ -0,0 +1,749 @@
+package json
+
+import (
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// An InvalidUnmarshalError describes an invalid argument passed to Unmarshal.
+// (The argument to Unmarshal must be a non-nil pointer.)
+type InvalidUnmarshalError struct {
+	Type reflect.Type
+}
+
+func (e *InvalidUnmarshalError) Error() string {
+	if e.Type == nil {
+		return "json: Unmarshal(nil)"
+	}
+	if e.Type.Kind() != reflect.Ptr {
+		return "json: Unmarshal(non-pointer " + e.Type.String() + ")"
+	}
+	return "json: Unmarshal(nil " + e.Type.String() + ")"
+}
+
+// A FieldError describes an object member that has no matching struct
+// field under Decoder.DisallowUnknownFields, or a field tagged
+// `json:",required"` that is missing from its object.
+type FieldError struct {
+	Path    string // JSON Pointer (RFC 6901) of the member within the decoded value
+	Missing bool   // a required field is missing, rather than an unknown member present
+}
+
+func (e *FieldError) Error() string {
+	if e.Missing {
+		return "json: missing required field " + strconv.Quote(e.Path)
+	}
+	return "json: unknown field " + strconv.Quote(e.Path)
+}
+
+// FieldErrors is returned by Unmarshal and Decoder.Decode when one or
+// more FieldErrors were found. It lists all of them in document order;
+// the rest of the value is decoded regardless. FieldErrors returned by
+// an UnmarshalJSON method are included, with paths relative to the
+// outermost value.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fe := range e {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// Unmarshaler is the interface implemented by types
+// that can unmarshal a JSON description of themselves.
+// The input is a valid, compact encoding of a JSON value.
+// It matches encoding/json.Unmarshaler, so existing
+// implementations work unchanged.
+type Unmarshaler interface {
+	UnmarshalJSON([]byte) error
+}
+
+// Unmarshal parses the JSON-encoded data and stores the result
+// in the value pointed to by v.
+//
+// If a value on the way implements Unmarshaler, including through a
+// pointer receiver, Unmarshal calls its UnmarshalJSON method, also
+// for a JSON null. A nil pointer receives a JSON null by being left
+// nil instead. Otherwise a JSON string is passed to the UnmarshalText
+// method of a value implementing encoding.TextUnmarshaler.
+//
+// Objects decode into maps keyed by strings, integers or types
+// implementing encoding.TextUnmarshaler.
+//
+// To unmarshal into an empty interface value, Unmarshal stores one of
+// nil, bool, float64, string, []interface{} and map[string]interface{}.
+// A Decoder can store Number instead of float64 (UseNumber) and
+// *OrderedMap instead of map[string]interface{} (UseOrderedMap).
+//
+// Struct fields tagged `json:",required"` must be present in the
+// object, possibly as null. Missing fields, and unknown members under
+// Decoder.DisallowUnknownFields, are reported together as FieldErrors.
+func Unmarshal(data []byte, v interface{}) error {
+	// 生成的类型直接用 Lexer 解码，不构建 Value 树
+	if ld, ok := v.(LexerDecoder); ok {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			return decodeLexer(ld, data)
+		}
+	}
+	jv, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	return unmarshalValue(jv, v)
+}
+
+// unmarshalValue 把已经解析好的 jsonValue 树写入 v 指向的变量
+func unmarshalValue(jv *jsonValue, v interface{}) error {
+	return (&decodeState{}).unmarshal(jv, v)
+}
+
+// decodeState 是把 jsonValue 写入 Go 变量时的状态
+type decodeState struct {
+	useNumber       bool // interface{} 中的数字使用 Number
+	orderedMap      bool // interface{} 中的对象使用 *OrderedMap
+	disallowUnknown bool // 结构体中没有对应字段的成员视为错误
+	path            []pathElem
+	fieldErrs       FieldErrors
+}
+
+// pathElem 是当前位置的一级路径，对象成员名或数组下标。只在报告 FieldError 时才转成字符串
+type pathElem struct {
+	key   []byte
+	index int // 对象成员为 -1
+}
+
+func (d *decodeState) unmarshal(jv *jsonValue, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &InvalidUnmarshalError{reflect.TypeOf(v)}
+	}
+	if err := d.value(jv, rv.Elem()); err != nil {
+		return err
+	}
+	if len(d.fieldErrs) > 0 {
+		return d.fieldErrs
+	}
+	return nil
+}
+
+// fieldError 记录当前对象中名为 name 的成员的错误，继续解码其余部分
+func (d *decodeState) fieldError(name string, missing bool) {
+	d.fieldErrs = append(d.fieldErrs, &FieldError{Path: formatPath(d.path) + "/" + escapePointer(name), Missing: missing})
+}
+
+// formatPath 把路径转成 JSON Pointer
+func formatPath(path []pathElem) string {
+	var b strings.Builder
+	for _, p := range path {
+		b.WriteByte('/')
+		if p.index >= 0 {
+			b.WriteString(strconv.Itoa(p.index))
+		} else {
+			b.WriteString(escapePointer(string(p.key)))
+		}
+	}
+	return b.String()
+}
+
+func (d *decodeState) value(jv *jsonValue, v reflect.Value) error {
+	return typeDecoder(v.Type())(d, jv, v)
+}
+
+// decoderFunc 把 jv 写入 v，每种类型编译一次后缓存在 decoderCache 中
+type decoderFunc func(d *decodeState, jv *jsonValue, v reflect.Value) error
+
+var decoderCache sync.Map // map[reflect.Type]decoderFunc
+
+// typeDecoder 返回类型 t 的解码函数，递归类型的处理与 typeEncoder 相同
+func typeDecoder(t reflect.Type) decoderFunc {
+	if fi, ok := decoderCache.Load(t); ok {
+		return fi.(decoderFunc)
+	}
+	var (
+		wg sync.WaitGroup
+		f  decoderFunc
+	)
+	wg.Add(1)
+	fi, loaded := decoderCache.LoadOrStore(t, decoderFunc(func(d *decodeState, jv *jsonValue, v reflect.Value) error {
+		wg.Wait()
+		return f(d, jv, v)
+	}))
+	if loaded {
+		return fi.(decoderFunc)
+	}
+	f = newTypeDecoder(t)
+	wg.Done()
+	decoderCache.Store(t, f)
+	return f
+}
+
+func newTypeDecoder(t reflect.Type) decoderFunc {
+	switch t {
+	case valuePtrType:
+		return valuePtrDecoder
+	case rawMessageType:
+		return rawMessageDecoder
+	}
+	if t.Kind() == reflect.Ptr {
+		return newPtrDecoder(t)
+	}
+	// 可寻址的值优先使用指针接收者的方法
+	if t.Name() != "" {
+		pt := reflect.PointerTo(t)
+		if pt.Implements(unmarshalerType) {
+			return newCondAddrDecoder(addrUnmarshalerDecoder, newKindDecoder(t))
+		}
+		if pt.Implements(textUnmarshalerType) {
+			return newCondAddrDecoder(addrTextUnmarshalerDecoder, newKindDecoder(t))
+		}
+	}
+	return newKindDecoder(t)
+}
+
+// newKindDecoder 按 t 的 Kind 编译解码函数，不考虑 Unmarshaler
+func newKindDecoder(t reflect.Type) decoderFunc {
+	switch t.Kind() {
+	case reflect.Interface:
+		return interfaceDecoder
+	case reflect.Bool:
+		return boolDecoder
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return numberDecoder
+	case reflect.String:
+		return stringDecoder
+	case reflect.Slice:
+		return newSliceDecoder(t)
+	case reflect.Array:
+		return newArrayDecoder(t)
+	case reflect.Map:
+		return newMapDecoder(t)
+	case reflect.Struct:
+		return newStructDecoder(t)
+	default:
+		return unsupportedTypeDecoder
+	}
+}
+
+var unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+
+// newCondAddrDecoder 可寻址时使用 canAddrDec，否则使用 elseDec
+func newCondAddrDecoder(canAddrDec, elseDec decoderFunc) decoderFunc {
+	return func(d *decodeState, jv *jsonValue, v reflect.Value) error {
+		if v.CanAddr() {
+			return canAddrDec(d, jv, v)
+		}
+		return elseDec(d, jv, v)
+	}
+}
+
+func valuePtrDecoder(d *decodeState, jv *jsonValue, v reflect.Value) error {
+	if jv.getValueType() == ValueNull {
+		v.Set(reflect.Zero(v.Type()))
+	} else {
+		v.Set(reflect.ValueOf(jv))
+	}
+	return nil
+}
+
+// callUnmarshaler 以压缩后的 JSON 调用 UnmarshalJSON，实现了 LexerDecoder 且有原文时直接从原文解码。
+// 返回的 FieldErrors 加上当前路径后记录下来，继续解码其余部分
+func (d *decodeState) callUnmarshaler(u Unmarshaler, jv *jsonValue) error {
+	var err error
+	if ld, ok := u.(LexerDecoder); ok && jv.text != nil {
+		err = decodeLexer(ld, jv.text)
+	} else {
+		var b []byte
+		if b, err = appendValue(nil, jv); err != nil {
+			return err
+		}
+		err = u.UnmarshalJSON(b)
+	}
+	if mergeFieldErrors(&d.fieldErrs, d.path, err) {
+		return nil
+	}
+	return err
+}
+
+// decodeLexer 用 Lexer 从 data 解码到 ld
+func decodeLexer(ld LexerDecoder, data []byte) error {
+	l := NewLexer(data)
+	ld.DecodeJSON(l)
+	return l.End()
+}
+
+// mergeFieldErrors 在 err 是 FieldErrors 时给其中的路径加上前缀 path 后追加到 dst，
+// err 为 nil 时也返回 true，其它错误返回 false
+func mergeFieldErrors(dst *FieldErrors, path []pathElem, err error) bool {
+	if err == nil {
+		return true
+	}
+	var fes FieldErrors
+	if !errors.As(err, &fes) {
+		return false
+	}
+	prefix := formatPath(path)
+	for _, fe := range fes {
+		*dst = append(*dst, &FieldError{Path: prefix + fe.Path, Missing: fe.Missing})
+	}
+	return true
+}
+
+func addrUnmarshalerDecoder(d *decodeState, jv *jsonValue, v reflect.Value) error {
+	return d.callUnmarshaler(v.Addr().Interface().(Unmarshaler), jv)
+}
+
+func addrTextUnmarshalerDecoder(d *decodeState, jv *jsonValue, v reflect.Value) error {
+	switch jv.getValueType() {
+	case ValueNull:
+		setNull(v)
+		return nil
+	case ValueString:
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(jv.s)
+	}
+	return d.typeError(jv, v)
+}
+
+// setNull 把 null 写入 v：可以为 nil 的类型置为 nil，其余类型保持不变
+func setNull(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+		v.Set(reflect.Zero(v.Type()))
+	}
+}
+
+// newPtrDecoder 解码 null 时把可设置的指针置为 nil，否则必要时分配新的值，
+// 指针实现了 Unmarshaler 或 encoding.TextUnmarshaler 时调用它，不然解码到指向的值
+func newPtrDecoder(t reflect.Type) decoderFunc {
+	// *RawMessage 解码到指向的 RawMessage，以便保存原文
+	isUnmarshaler := t.Implements(unmarshalerType) && t.Elem() != rawMessageType
+	isTextUnmarshaler := t.Implements(textUnmarshalerType)
+	var elemDec decoderFunc
+	if !isUnmarshaler && !isTextUnmarshaler {
+		elemDec = typeDecoder(t.Elem())
+	}
+	return func(d *decodeState, jv *jsonValue, v reflect.Value) error {
+		null := jv.getValueType() == ValueNull
+		if null && v.CanSet() {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		switch {
+		case isUnmarshaler:
+			return d.callUnmarshaler(v.Interface().(Unmarshaler), jv)
+		case isTextUnmarshaler:
+			if null {
+				return nil
+			}
+			if jv.getValueType() != ValueString {
+				return d.typeError(jv, v)
+			}
+			return v.Interface().(encoding.TextUnmarshaler).UnmarshalText(jv.s)
+		}
+		return elemDec(d, jv, v.Elem())
+	}
+}
+
+func interfaceDecoder(d *decodeState, jv *jsonValue, v reflect.Value) error {
+	null := jv.getValueType() == ValueNull
+	// interface 中已有非 nil 指针时，解码到它指向的值
+	if !v.IsNil() {
+		e := v.Elem()
+		if e.Kind() == reflect.Ptr && !e.IsNil() && (!null || e.Elem().Kind() == reflect.Ptr) {
+			return typeDecoder(e.Type())(d, jv, e)
+		}
+	}
+	if null {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.NumMethod() > 0 {
+		return d.typeError(jv, v)
+	}
+	x, err := d.valueInterface(jv)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(&x).Elem())
+	return nil
+}
+
+func boolDecoder(d *decodeState, jv *jsonValue, v reflect.Value) error {
+	switch jv.getValueType() {
+	case ValueNull:
+		return nil
+	case ValueTrue, ValueFalse:
+		v.SetBool(jv.valueType == ValueTrue)
+		return nil
+	}
+	return d.typeError(jv, v)
+}
+
+func numberDecoder(d *decodeState, jv *jsonValue, v reflect.Value) error {
+	switch jv.getValueType() {
+	case ValueNull:
+		return nil
+	case ValueNumber:
+		return d.number(jv, v)
+	}
+	return d.typeError(jv, v)
+}
+
+func stringDecoder(d *decodeState, jv *jsonValue, v reflect.Value) error {
+	switch jv.getValueType() {
+	case ValueNull:
+		return nil
+	case ValueString:
+		v.SetString(string(jv.s))
+		return nil
+	}
+	return d.typeError(jv, v)
+}
+
+func unsupportedTypeDecoder(d *decodeState, jv *jsonValue, v reflect.Value) error {
+	if jv.getValueType() == ValueNull {
+		setNull(v)
+		return nil
+	}
+	return d.typeError(jv, v)
+}
+
+func newSliceDecoder(t reflect.Type) decoderFunc {
+	elemDec := typeDecoder(t.Elem())
+	isBytes := t.Elem().Kind() == reflect.Uint8
+	return func(d *decodeState, jv *jsonValue, v reflect.Value) error {
+		switch jv.getValueType() {
+		case ValueNull:
+			v.Set(reflect.Zero(t))
+			return nil
+		case ValueString:
+			// []byte 从 base64 字符串解码
+			if !isBytes {
+				break
+			}
+			b, err := base64.StdEncoding.DecodeString(string(jv.s))
+			if err != nil {
+				return err
+			}
+			v.SetBytes(b)
+			return nil
+		case ValueArray:
+			if err := jv.load(); err != nil {
+				return err
+			}
+			n := jv.getArrayLen()
+			v.Set(reflect.MakeSlice(t, n, n))
+			return d.elements(jv, v, n, elemDec)
+		}
+		return d.typeError(jv, v)
+	}
+}
+
+func newArrayDecoder(t reflect.Type) decoderFunc {
+	elemDec := typeDecoder(t.Elem())
+	zero := reflect.Zero(t.Elem())
+	return func(d *decodeState, jv *jsonValue, v reflect.Value) error {
+		switch jv.getValueType() {
+		case ValueNull:
+			return nil
+		case ValueArray:
+			if err := jv.load(); err != nil {
+				return err
+			}
+			// 多余的元素丢弃，不足的部分置零
+			n := jv.getArrayLen()
+			for i := n; i < v.Len(); i++ {
+				v.Index(i).Set(zero)
+			}
+			return d.elements(jv, v, min(n, v.Len()), elemDec)
+		}
+		return d.typeError(jv, v)
+	}
+}
+
+// elements 把数组 jv 的前 n 个元素依次写入 v
+func (d *decodeState) elements(jv *jsonValue, v reflect.Value, n int, elemDec decoderFunc) error {
+	for i := 0; i < n; i++ {
+		d.path = append(d.path, pathElem{index: i})
+		if err := elemDec(d, jv.array.values[i], v.Index(i)); err != nil {
+			return err
+		}
+		d.path = d.path[:len(d.path)-1]
+	}
+	return nil
+}
+
+func newMapDecoder(t reflect.Type) decoderFunc {
+	kt := t.Key()
+	switch kt.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		if !reflect.PointerTo(kt).Implements(textUnmarshalerType) {
+			return unsupportedTypeDecoder
+		}
+	}
+	elemDec := typeDecoder(t.Elem())
+	return func(d *decodeState, jv *jsonValue, v reflect.Value) error {
+		switch jv.getValueType() {
+		case ValueNull:
+			v.Set(reflect.Zero(t))
+			return nil
+		case ValueObject:
+		default:
+			return d.typeError(jv, v)
+		}
+		if err := jv.load(); err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(t))
+		}
+		elem := reflect.New(t.Elem()).Elem()
+		zero := reflect.Zero(t.Elem())
+		for i := 0; i < jv.getObjectSize(); i++ {
+			name := jv.object.keys[i].s
+			elem.Set(zero)
+			d.path = append(d.path, pathElem{key: name, index: -1})
+			if err := elemDec(d, jv.object.values[i], elem); err != nil {
+				return err
+			}
+			d.path = d.path[:len(d.path)-1]
+			key, err := d.mapKey(name, kt)
+			if err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+		return nil
+	}
+}
+
+func newStructDecoder(t reflect.Type) decoderFunc {
+	fields := cachedTypeFields(t)
+	decoders := make([]decoderFunc, len(fields.list))
+	for i := range fields.list {
+		decoders[i] = typeDecoder(typeByIndex(t, fields.list[i].index))
+	}
+	return func(d *decodeState, jv *jsonValue, v reflect.Value) error {
+		switch jv.getValueType() {
+		case ValueNull:
+			return nil
+		case ValueObject:
+		default:
+			return d.typeError(jv, v)
+		}
+		if err := jv.load(); err != nil {
+			return err
+		}
+		var seen []bool
+		if fields.required {
+			seen = make([]bool, len(fields.list))
+		}
+		for i := 0; i < jv.getObjectSize(); i++ {
+			key := jv.object.keys[i].s
+			f := fields.lookup(key)
+			if f < 0 {
+				if d.disallowUnknown {
+					d.fieldError(string(key), false)
+				}
+				continue
+			}
+			if seen != nil {
+				seen[f] = true
+			}
+			fv, err := allocFieldByIndex(v, fields.list[f].index)
+			if err != nil {
+				return err
+			}
+			d.path = append(d.path, pathElem{key: key, index: -1})
+			if err := decoders[f](d, jv.object.values[i], fv); err != nil {
+				return err
+			}
+			d.path = d.path[:len(d.path)-1]
+		}
+		for f := range seen {
+			if fields.list[f].required && !seen[f] {
+				d.fieldError(fields.list[f].name, true)
+			}
+		}
+		return nil
+	}
+}
+
+// valueInterface 把 jsonValue 转成 interface{} 中的默认类型
+func (d *decodeState) valueInterface(jv *jsonValue) (interface{}, error) {
+	if err := jv.load(); err != nil {
+		return nil, err
+	}
+	switch jv.getValueType() {
+	case ValueTrue, ValueFalse:
+		return jv.valueType == ValueTrue, nil
+	case ValueNumber:
+		if !d.useNumber {
+			return jv.n, nil
+		}
+		if len(jv.s) == 0 {
+			return Number(strconv.FormatFloat(jv.n, 'g', -1, 64)), nil
+		}
+		return Number(jv.s), nil
+	case ValueString:
+		return string(jv.s), nil
+	case ValueArray:
+		a := make([]interface{}, jv.getArrayLen())
+		for i := range a {
+			x, err := d.valueInterface(jv.array.values[i])
+			if err != nil {
+				return nil, err
+			}
+			a[i] = x
+		}
+		return a, nil
+	case ValueObject:
+		if d.orderedMap {
+			m := &OrderedMap{}
+			for i := 0; i < jv.getObjectSize(); i++ {
+				x, err := d.valueInterface(jv.object.values[i])
+				if err != nil {
+					return nil, err
+				}
+				m.Set(string(jv.object.keys[i].s), x)
+			}
+			return m, nil
+		}
+		m := make(map[string]interface{}, jv.getObjectSize())
+		for i := 0; i < jv.getObjectSize(); i++ {
+			x, err := d.valueInterface(jv.object.values[i])
+			if err != nil {
+				return nil, err
+			}
+			m[string(jv.object.keys[i].s)] = x
+		}
+		return m, nil
+	}
+	return nil, nil
+}
+
+func (d *decodeState) number(jv *jsonValue, v reflect.Value) error {
+	s := string(jv.s)
+	if len(jv.s) == 0 {
+		// JSON5 和查询得到的数字没有原文，整数按 float64 的值解析
+		s = strconv.FormatFloat(jv.n, 'f', -1, 64)
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || v.OverflowInt(n) {
+			return d.typeError(jv, v)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || v.OverflowUint(n) {
+			return d.typeError(jv, v)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if v.OverflowFloat(jv.n) {
+			return d.typeError(jv, v)
+		}
+		v.SetFloat(jv.n)
+	default:
+		return d.typeError(jv, v)
+	}
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// mapKey 把对象成员名转成类型为 kt 的 map key：TextUnmarshaler 优先，其次是字符串和整数
+func (d *decodeState) mapKey(name []byte, kt reflect.Type) (reflect.Value, error) {
+	if reflect.PointerTo(kt).Implements(textUnmarshalerType) {
+		kv := reflect.New(kt)
+		if err := kv.Interface().(encoding.TextUnmarshaler).UnmarshalText(name); err != nil {
+			return reflect.Value{}, err
+		}
+		return kv.Elem(), nil
+	}
+	kv := reflect.New(kt).Elem()
+	switch kt.Kind() {
+	case reflect.String:
+		kv.SetString(string(name))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(string(name), 10, 64)
+		if err != nil || kv.OverflowInt(n) {
+			return reflect.Value{}, &UnmarshalTypeError{Value: "number " + string(name), Type: kt}
+		}
+		kv.SetInt(n)
+	default:
+		n, err := strconv.ParseUint(string(name), 10, 64)
+		if err != nil || kv.OverflowUint(n) {
+			return reflect.Value{}, &UnmarshalTypeError{Value: "number " + string(name), Type: kt}
+		}
+		kv.SetUint(n)
+	}
+	return kv, nil
+}
+
+// allocFieldByIndex 沿 index 取字段，途中遇到 nil 的嵌入指针时分配新的值
+func allocFieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				// 未导出的嵌入指针无法赋值
+				if !v.CanSet() {
+					return reflect.Value{}, errors.New("json: cannot set embedded pointer to unexported struct: " + v.Type().Elem().String())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, nil
+}
+
+func (d *decodeState) typeError(jv *jsonValue, v reflect.Value) error {
+	return newTypeError(jv, v.Type())
+}
+
+// newTypeError 描述无法把 jv 写入类型 t 的错误
+func newTypeError(jv *jsonValue, t reflect.Type) error {
+	var desc string
+	switch jv.getValueType() {
+	case ValueTrue, ValueFalse:
+		desc = "bool"
+	case ValueNumber:
+		desc = "number " + string(jv.s)
+		if len(jv.s) == 0 {
+			desc = "number " + strconv.FormatFloat(jv.n, 'g', -1, 64)
+		}
+	case ValueString:
+		desc = "string"
+	case ValueArray:
+		desc = "array"
+	case ValueObject:
+		desc = "object"
+	}
+	return &UnmarshalTypeError{Value: desc, Type: t}
+}
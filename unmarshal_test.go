@@ -0,0 +1,407 @@
+package json
+
+import (
+	stdjson "encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testUnmarshalError(t *testing.T, data string, v interface{}, msg string) {
+	t.Helper()
+	err := Unmarshal([]byte(data), v)
+	if err == nil {
+		t.Errorf("data %s should be error, but pass", data)
+		return
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Errorf("Data %s Should be error is [%s], but error is [%s]", data, msg, err.Error())
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var u marshalUser
+	err := Unmarshal([]byte(`{"name":"bob","AGE":3,"tags":["a","b"],"extra":{"x":true},"Secret":"s",
+		"friend":{"name":"amy"},"scores":[1,2,3],"data":"AQI=","default":"d","unknown":[1]}`), &u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, marshalUser{
+		Name:    "bob",
+		Age:     3,
+		Tags:    []string{"a", "b"},
+		Extra:   map[string]bool{"x": true},
+		Friend:  &marshalUser{Name: "amy"},
+		Scores:  [2]float64{1, 2},
+		Data:    []byte{1, 2},
+		Default: "d",
+	}, u)
+
+	var n int64
+	if err := Unmarshal([]byte("9223372036854775807"), &n); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, int64(9223372036854775807), n)
+
+	p := &n
+	if err := Unmarshal([]byte("null"), &p); err != nil {
+		t.Fatal(err)
+	}
+	assertTrue(t, p == nil)
+
+	var v *Value
+	if err := Unmarshal([]byte(`{"a":1}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 1, v.getObjectSize())
+}
+
+func TestUnmarshalError(t *testing.T) {
+	var i int8
+	testUnmarshalError(t, "300", &i, "cannot unmarshal number 300 into Go jsonValue of type int8")
+	testUnmarshalError(t, "1.5", &i, "cannot unmarshal number 1.5")
+	testUnmarshalError(t, `"a"`, &i, "cannot unmarshal string")
+	var s []string
+	testUnmarshalError(t, `{}`, &s, "cannot unmarshal object")
+	testUnmarshalError(t, `[1`, &s, "MISS_COMMA_OR_SQUARE_BRACKET")
+	testUnmarshalError(t, `1`, s, "json: Unmarshal(non-pointer []string)")
+	testUnmarshalError(t, `1`, nil, "json: Unmarshal(nil)")
+}
+
+// upper 以指针接收者实现 Unmarshaler，记录收到的原文
+type upper struct {
+	raw string
+}
+
+func (u *upper) UnmarshalJSON(b []byte) error {
+	if string(b) == `"fail"` {
+		return errors.New("upper: fail")
+	}
+	u.raw = strings.ToUpper(string(b))
+	return nil
+}
+
+func TestUnmarshaler(t *testing.T) {
+	var v struct {
+		A upper
+		B *upper
+		C []upper
+		D map[string]*upper
+		N upper
+		P *upper
+	}
+	v.P = &upper{"old"}
+	err := Unmarshal([]byte(`{"A":"a","B":{ "x" : [1, 2.50] },"C":["c",true],"D":{"k":"d"},"N":null,"P":null}`), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, `"A"`, v.A.raw)
+	assertEqual(t, `{"X":[1,2.50]}`, v.B.raw)
+	assertEqual(t, []upper{{`"C"`}, {`TRUE`}}, v.C)
+	assertEqual(t, `"D"`, v.D["k"].raw)
+	// 非指针的值在 null 时也会调用 UnmarshalJSON，nil 指针则直接置空
+	assertEqual(t, `NULL`, v.N.raw)
+	assertTrue(t, v.P == nil)
+
+	testUnmarshalError(t, `{"A":"fail"}`, &v, "upper: fail")
+}
+
+func TestUnmarshalerStd(t *testing.T) {
+	// 实现了 encoding/json 接口的类型可以直接使用
+	want := time.Date(2024, 2, 29, 12, 30, 0, 5, time.UTC)
+	b, err := Marshal(map[string]time.Time{"at": want})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, `{"at":"2024-02-29T12:30:00.000000005Z"}`, string(b))
+	var got struct{ At time.Time }
+	if err := Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	assertTrue(t, want.Equal(got.At))
+	testUnmarshalError(t, `{"At":"yesterday"}`, &got, `cannot parse "yesterday"`)
+}
+
+func TestUnmarshalText(t *testing.T) {
+	var v struct {
+		ID  userID
+		P   *userID
+		IPs []net.IP
+	}
+	if err := Unmarshal([]byte(`{"ID":"u-3","P":"u-4","IPs":["10.0.0.1","::1"]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, userID{3}, v.ID)
+	assertEqual(t, &userID{4}, v.P)
+	assertEqual(t, "10.0.0.1,::1", v.IPs[0].String()+","+v.IPs[1].String())
+
+	if err := Unmarshal([]byte(`{"P":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	assertTrue(t, v.P == nil)
+
+	testUnmarshalError(t, `{"ID":"x"}`, &v, "bad id x")
+	testUnmarshalError(t, `{"ID":3}`, &v, "cannot unmarshal number 3 into Go jsonValue of type json.userID")
+}
+
+func TestUnmarshalMapKey(t *testing.T) {
+	var ints map[int]string
+	if err := Unmarshal([]byte(`{"-1":"a","2":"b"}`), &ints); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, map[int]string{-1: "a", 2: "b"}, ints)
+
+	var uints map[uint64]bool
+	if err := Unmarshal([]byte(`{"18446744073709551615":true}`), &uints); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, map[uint64]bool{18446744073709551615: true}, uints)
+
+	var ids map[userID]int
+	if err := Unmarshal([]byte(`{"u-1":1,"u-2":2}`), &ids); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, map[userID]int{{1}: 1, {2}: 2}, ids)
+
+	testUnmarshalError(t, `{"300":1}`, &map[int8]int{}, "cannot unmarshal number 300 into Go jsonValue of type int8")
+	testUnmarshalError(t, `{"-1":1}`, &map[uint]int{}, "cannot unmarshal number -1 into Go jsonValue of type uint")
+	testUnmarshalError(t, `{"x":1}`, &map[int]int{}, "cannot unmarshal number x into Go jsonValue of type int")
+	testUnmarshalError(t, `{"x":1}`, &map[userID]int{}, "bad id x")
+	testUnmarshalError(t, `{"1":1}`, &map[float64]int{}, "cannot unmarshal object into Go jsonValue of type map[float64]int")
+}
+
+func TestUnmarshalInterface(t *testing.T) {
+	var v interface{}
+	if err := Unmarshal([]byte(`{"a":[1,"s",true,null,{"b":-2.5e3}],"c":{}}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, map[string]interface{}{
+		"a": []interface{}{1.0, "s", true, nil, map[string]interface{}{"b": -2500.0}},
+		"c": map[string]interface{}{},
+	}, v)
+
+	var s struct {
+		Any  interface{}
+		List []interface{}
+		Map  map[string]interface{}
+	}
+	if err := Unmarshal([]byte(`{"Any":"x","List":[1,[]],"Map":{"k":false}}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "x", s.Any)
+	assertEqual(t, []interface{}{1.0, []interface{}{}}, s.List)
+	assertEqual(t, map[string]interface{}{"k": false}, s.Map)
+
+	// interface 中已有非 nil 指针时解码到指针指向的值
+	n := 1
+	v = &n
+	if err := Unmarshal([]byte(`5`), &v); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, 5, n)
+
+	if err := Unmarshal([]byte(`null`), &v); err != nil {
+		t.Fatal(err)
+	}
+	assertTrue(t, v == nil)
+
+	var r interface{ Read([]byte) (int, error) }
+	testUnmarshalError(t, `"x"`, &r, "cannot unmarshal string into Go jsonValue of type interface")
+}
+
+type strictItem struct {
+	ID   int    `json:"id,required"`
+	Name string `json:"name,omitempty,required"`
+	Note string `json:"note"`
+}
+
+type strictRequest struct {
+	Kind  string                `json:"kind,required"`
+	Items []strictItem          `json:"items"`
+	ByKey map[string]strictItem `json:"by_key"`
+	Owner *strictItem           `json:"owner,required"`
+}
+
+func TestUnmarshalRequired(t *testing.T) {
+	var r strictRequest
+	err := Unmarshal([]byte(`{"kind":"k","owner":null,"items":[{"id":1,"name":"a"},{"name":"b"}],"by_key":{"a/b":{"id":2}}}`), &r)
+	fe, ok := err.(FieldErrors)
+	assertTrue(t, ok)
+	assertEqual(t, `json: missing required field "/items/1/id"; json: missing required field "/by_key/a~1b/name"`, err.Error())
+	assertEqual(t, 2, len(fe))
+	assertTrue(t, fe[0].Missing)
+	// 其余部分照常解码
+	assertEqual(t, "b", r.Items[1].Name)
+	assertEqual(t, 2, r.ByKey["a/b"].ID)
+
+	var missing *FieldError
+	assertTrue(t, errors.As(err, &missing) && missing.Path == "/items/1/id")
+
+	err = Unmarshal([]byte(`{"items":[]}`), &r)
+	assertEqual(t, `json: missing required field "/kind"; json: missing required field "/owner"`, err.Error())
+
+	// 没有开启 DisallowUnknownFields 时忽略未知成员
+	err = Unmarshal([]byte(`{"kind":"k","owner":{"id":1,"name":"n","x":1},"y":2}`), &r)
+	assertTrue(t, err == nil)
+}
+
+// wrappedItem 的 UnmarshalJSON 返回的 FieldErrors 被并入外层的结果
+type wrappedItem struct{ strictItem }
+
+func (w *wrappedItem) UnmarshalJSON(b []byte) error {
+	return Unmarshal(b, &w.strictItem)
+}
+
+func TestUnmarshalerFieldErrors(t *testing.T) {
+	var v struct {
+		A []wrappedItem `json:"a"`
+		B int           `json:"b,required"`
+	}
+	err := Unmarshal([]byte(`{"a":[{"id":1,"name":"x"},{"id":2}]}`), &v)
+	assertEqual(t, `json: missing required field "/a/1/name"; json: missing required field "/b"`, err.Error())
+	assertEqual(t, 2, v.A[1].ID)
+}
+
+func TestDisallowUnknownFields(t *testing.T) {
+	var r strictRequest
+	dec := NewBytesDecoder([]byte(`{"kind":"k","extra":1,"owner":{"id":1,"name":"o","x~":[1]},
+		"items":[{"id":1,"name":"a","Note":"n"},{"id":2,"nam":"b"}],"by_key":{"k":{"id":3,"name":"c","z":null}}}`))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(&r)
+	assertEqual(t, FieldErrors{
+		{Path: "/extra"},
+		{Path: "/owner/x~0"},
+		{Path: "/items/1/nam"},
+		{Path: "/items/1/name", Missing: true},
+		{Path: "/by_key/k/z"},
+	}, err)
+	assertEqual(t, "n", r.Items[0].Note)
+
+	// map 和 interface{} 接受任意成员
+	var m map[string]interface{}
+	dec = NewBytesDecoder([]byte(`{"a":{"b":1}}`))
+	dec.DisallowUnknownFields()
+	assertTrue(t, dec.Decode(&m) == nil)
+}
+
+func TestUnmarshalEmbedded(t *testing.T) {
+	var m embedModel
+	err := Unmarshal([]byte(`{"created":1,"updated":2,"id":"m1","Kind":"k","Note":"n","by":"bob","Name":"outer","embedTag":"x"}`), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nil 的嵌入指针被分配
+	assertEqual(t, &Metadata{ID: "m1"}, m.Metadata)
+	assertEqual(t, Timestamps{Created: 1, Updated: 2}, m.Timestamps)
+	assertEqual(t, Labels{Note: "n"}, m.Labels)
+	assertEqual(t, "bob", m.By)
+	assertEqual(t, "outer", m.Name)
+	assertEqual(t, embedTag(""), m.embedTag)
+
+	type hidden struct{ X int }
+	var p struct{ *hidden }
+	testUnmarshalError(t, `{"X":1}`, &p, "json: cannot set embedded pointer to unexported struct: json.hidden")
+}
+
+// treeNode 是递归类型，检验编译期间的占位函数
+type treeNode struct {
+	Name     string      `json:"name"`
+	Children []*treeNode `json:"children,omitempty"`
+}
+
+func TestCodecCacheConcurrent(t *testing.T) {
+	data := `{"name":"a","children":[{"name":"b"},{"name":"c","children":[{"name":"d"}]}]}`
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var n treeNode
+			if err := Unmarshal([]byte(data), &n); err != nil {
+				errs <- err
+				return
+			}
+			b, err := Marshal(&n)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if string(b) != data {
+				errs <- errors.New("round trip mismatch: " + string(b))
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	_, ok := decoderCache.Load(reflect.TypeOf(treeNode{}))
+	assertTrue(t, ok)
+	_, ok = encoderCache.Load(reflect.TypeOf(treeNode{}))
+	assertTrue(t, ok)
+}
+
+// benchUser 与 benchData 中的元素对应
+type benchUser struct {
+	ID      int      `json:"id"`
+	Name    string   `json:"name"`
+	Active  bool     `json:"active"`
+	Score   float64  `json:"score"`
+	Tags    []string `json:"tags"`
+	Profile struct {
+		City string  `json:"city"`
+		Zip  *string `json:"zip"`
+	} `json:"profile"`
+}
+
+func BenchmarkUnmarshalStruct(b *testing.B) {
+	data := benchData()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		var v []benchUser
+		if err := Unmarshal(data, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStdUnmarshalStruct(b *testing.B) {
+	data := benchData()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		var v []benchUser
+		if err := stdjson.Unmarshal(data, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalStruct(b *testing.B) {
+	var v []benchUser
+	if err := Unmarshal(benchData(), &v); err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := Marshal(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStdMarshalStruct(b *testing.B) {
+	var v []benchUser
+	if err := Unmarshal(benchData(), &v); err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := stdjson.Marshal(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -14,6 +14,7 @@ const (
 	ValueString
 	ValueArray
 	ValueObject
+	ValueInvalid // 容错解析时表示无法解析的位置
 )
 
 // jsonValueError 访问 jsonValue 时的error
@@ -30,8 +31,16 @@ type jsonValue struct {
 	s         []byte
 	n         float64
 	valueType ValueType
+	text      []byte // 值在输入中的原文，用于 RawMessage 和按需解析
+	start     int    // 值在输入中的起始偏移
+	end       int    // 值在输入中的结束偏移，不含
+	err       error  // 按需解析失败时记录的错误
+	deferred  bool   // 按需解析模式下尚未展开的数组或对象
 }
 
+// Value is a parsed JSON document node.
+type Value = jsonValue
+
 type object struct {
 	size   int
 	keys   []*jsonValue
@@ -68,7 +77,11 @@ func (v *jsonValue) getString() (string, error) {
 	return string(v.s), nil
 }
 
+// getArrayLen 忽略 load 的错误，错误保存在 v.err 中，可以通过 Err 取得
 func (v *jsonValue) getArrayLen() int {
+	if v.load() != nil {
+		return 0
+	}
 	return v.array.len
 }
 
@@ -76,6 +89,9 @@ func (v *jsonValue) getArrayElem(index int) (*jsonValue, error) {
 	if v.valueType != ValueArray {
 		return nil, v.error("value type isn't array")
 	}
+	if err := v.load(); err != nil {
+		return nil, err
+	}
 	if index > v.array.len-1 {
 		return nil, errors.New("array out range")
 	}
@@ -83,6 +99,9 @@ func (v *jsonValue) getArrayElem(index int) (*jsonValue, error) {
 }
 
 func (v *jsonValue) getObjectSize() int {
+	if v.load() != nil {
+		return 0
+	}
 	return v.object.size
 }
 
@@ -90,6 +109,9 @@ func (v *jsonValue) getObjectKey(index int) (*jsonValue, error) {
 	if v.valueType != ValueObject {
 		return nil, v.error("value type isn't object")
 	}
+	if err := v.load(); err != nil {
+		return nil, err
+	}
 	if index > v.object.size-1 {
 		return nil, v.error("object out range")
 	}
@@ -100,6 +122,9 @@ func (v *jsonValue) getObjectValue(index int) (*jsonValue, error) {
 	if v.valueType != ValueObject {
 		return nil, v.error("value type isn't object")
 	}
+	if err := v.load(); err != nil {
+		return nil, err
+	}
 	if index > v.object.size-1 {
 		return nil, v.error("object out range")
 	}
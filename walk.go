@@ -0,0 +1,149 @@
+package json
+
+// Handler receives the events produced by Walk, in document order.
+// Returning a non-nil error from any method stops the walk and
+// Walk returns that error unchanged.
+type Handler interface {
+	OnNull() error
+	OnBool(b bool) error
+	OnNumber(n float64) error
+	OnString(s string) error
+	OnStartObject() error
+	OnKey(key string) error
+	OnEndObject() error
+	OnStartArray() error
+	OnEndArray() error
+}
+
+// walkParse 复用 jsonParse 的扫描逻辑，把解析结果以事件的形式交给 Handler，不生成 jsonValue 树
+type walkParse struct {
+	jsonParse
+	h       Handler
+	scratch jsonValue
+}
+
+// Walk parses data and reports each value to h without building a tree.
+func Walk(data []byte, h Handler) error {
+	d := &walkParse{h: h}
+	d.init(data)
+	d.skipWhiteSpace()
+	if err := d.walkValue(); err != nil {
+		return err
+	}
+	d.skipWhiteSpace()
+	if d.off < len(d.data) {
+		return &SyntaxError{msg: "unexpected end of JSON input", Offset: d.off}
+	}
+	return nil
+}
+
+func (d *walkParse) walkValue() error {
+	v := &d.scratch
+	switch d.pop() {
+	case 'n':
+		if err := d.parseLiteral([]byte("null"), v, ValueNull); err != nil {
+			return err
+		}
+		return d.h.OnNull()
+	case 't':
+		if err := d.parseLiteral([]byte("true"), v, ValueTrue); err != nil {
+			return err
+		}
+		return d.h.OnBool(true)
+	case 'f':
+		if err := d.parseLiteral([]byte("false"), v, ValueFalse); err != nil {
+			return err
+		}
+		return d.h.OnBool(false)
+	case '"':
+		if err := d.parseString(v); err != nil {
+			return err
+		}
+		return d.h.OnString(string(v.s))
+	case '[':
+		return d.walkArray()
+	case '{':
+		return d.walkObject()
+	default:
+		if err := d.parseNumber(v); err != nil {
+			return err
+		}
+		return d.h.OnNumber(v.n)
+	}
+}
+
+func (d *walkParse) walkArray() error {
+	if err := d.h.OnStartArray(); err != nil {
+		return err
+	}
+	d.next()
+	d.skipWhiteSpace()
+	if c := d.pop(); c == ']' {
+		d.next()
+		return d.h.OnEndArray()
+	}
+	for {
+		// 解析值
+		d.skipWhiteSpace()
+		if err := d.walkValue(); err != nil {
+			return err
+		}
+		// 分析是否有分隔符
+		d.skipWhiteSpace()
+		c := d.pop()
+		if c == ',' {
+			d.next()
+		} else if c == ']' {
+			d.next()
+			return d.h.OnEndArray()
+		} else {
+			return d.error(c, "MISS_COMMA_OR_SQUARE_BRACKET")
+		}
+	}
+}
+
+func (d *walkParse) walkObject() error {
+	if err := d.h.OnStartObject(); err != nil {
+		return err
+	}
+	d.next()
+	d.skipWhiteSpace()
+	c := d.pop()
+	if c == '}' {
+		d.next()
+		return d.h.OnEndObject()
+	}
+	for {
+		// 解析key
+		d.skipWhiteSpace()
+		if err := d.parseString(&d.scratch); err != nil {
+			return d.error(c, "miss key")
+		}
+		if err := d.h.OnKey(string(d.scratch.s)); err != nil {
+			return err
+		}
+		// 解析 ：字符
+		d.skipWhiteSpace()
+		c = d.pop()
+		if c != ':' {
+			return d.error(c, "miss colon")
+		}
+		d.next()
+		// 解析value
+		d.skipWhiteSpace()
+		if err := d.walkValue(); err != nil {
+			return err
+		}
+		// 解析分隔符、结束符
+		d.skipWhiteSpace()
+		c = d.pop()
+		if c == ',' {
+			d.next()
+		} else if c == '}' {
+			d.next()
+			return d.h.OnEndObject()
+		} else {
+			return d.error(c, "miss comma or curly bracket")
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recordHandler 把事件记录成字符串，便于断言
+type recordHandler struct {
+	events []string
+	stopAt string
+}
+
+var errStop = errors.New("stop")
+
+func (h *recordHandler) record(event string) error {
+	h.events = append(h.events, event)
+	if event == h.stopAt {
+		return errStop
+	}
+	return nil
+}
+
+func (h *recordHandler) OnNull() error            { return h.record("null") }
+func (h *recordHandler) OnBool(b bool) error      { return h.record(fmt.Sprint(b)) }
+func (h *recordHandler) OnNumber(n float64) error { return h.record(fmt.Sprint(n)) }
+func (h *recordHandler) OnString(s string) error  { return h.record(fmt.Sprintf("%q", s)) }
+func (h *recordHandler) OnStartObject() error     { return h.record("{") }
+func (h *recordHandler) OnKey(key string) error   { return h.record(key + ":") }
+func (h *recordHandler) OnEndObject() error       { return h.record("}") }
+func (h *recordHandler) OnStartArray() error      { return h.record("[") }
+func (h *recordHandler) OnEndArray() error        { return h.record("]") }
+
+func testWalkError(t *testing.T, data []byte, msg string) {
+	t.Helper()
+	err := Walk(data, &recordHandler{})
+	if err == nil {
+		t.Errorf("data %s should be error, but pass", data)
+		return
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Errorf("Data %s Should be error is [%s], but error is [%s]", data, msg, err.Error())
+	}
+}
+
+func TestWalk(t *testing.T) {
+	h := &recordHandler{}
+	err := Walk([]byte(` { "n" : null , "b" : [ true, false, [] ], "o" : { "s" : "x\ty", "i" : -1.5 }, "e" : {} } `), h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, []string{
+		"{", "n:", "null", "b:", "[", "true", "false", "[", "]", "]",
+		"o:", "{", "s:", `"x\ty"`, "i:", "-1.5", "}", "e:", "{", "}", "}",
+	}, h.events)
+}
+
+func TestWalkAbort(t *testing.T) {
+	h := &recordHandler{stopAt: "2"}
+	err := Walk([]byte(`[1, 2, 3, 4]`), h)
+	assertTrue(t, err == errStop)
+	assertEqual(t, []string{"[", "1", "2"}, h.events)
+}
+
+func TestWalkInvalid(t *testing.T) {
+	testWalkError(t, []byte("nul"), "parseJson type 0 error")
+	testWalkError(t, []byte("null x"), "unexpected end of JSON input")
+	testWalkError(t, []byte("[1 2"), "MISS_COMMA_OR_SQUARE_BRACKET")
+	testWalkError(t, []byte("{1:1}"), "miss key")
+	testWalkError(t, []byte("{\"a\"}"), "miss colon")
+	testWalkError(t, []byte("{\"a\": 1]"), "miss comma or curly bracket")
+}